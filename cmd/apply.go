@@ -0,0 +1,111 @@
+package cmd
+
+import (
+    "bufio"
+    "fmt"
+    "os"
+    "strings"
+    "time"
+
+    "go-mysqldiff/pkg/mysqldiff"
+
+    "github.com/spf13/cobra"
+)
+
+var (
+    applyFlags diffFlags
+
+    applyDryRun bool
+    applyYes    bool
+
+    applyCmd = &cobra.Command{
+        Use:   "apply",
+        Short: "在目标数据库上执行差异 SQL。",
+        Run: func(cmd *cobra.Command, args []string) {
+            sourceDatabase, targetDatabase, err := applyFlags.databases()
+
+            cobra.CheckErr(err)
+
+            if isSnapshot(applyFlags.target) || isDDL(applyFlags.target) {
+                cobra.CheckErr(fmt.Errorf("目标必须为服务器。"))
+            }
+
+            options, err := applyFlags.options()
+
+            cobra.CheckErr(err)
+
+            sourceCatalog, err := loadCatalog(cmd, applyFlags.source, sourceDatabase, "源", options.TableOptions)
+
+            cobra.CheckErr(err)
+
+            conn, err := openServer(applyFlags.target, targetDatabase, "目标")
+
+            cobra.CheckErr(err)
+
+            targetCatalog, err := loadConnCatalog(cmd, conn, targetDatabase, "目标", options.TableOptions)
+            targetCatalog, err = applyFlags.targetCatalog(targetCatalog, err, targetDatabase)
+
+            cobra.CheckErr(err)
+
+            result := mysqldiff.Compare(sourceCatalog, targetCatalog, options)
+            statements := result.Statements()
+
+            if len(statements) <= 0 {
+                fmt.Println("没有差异。")
+
+                return
+            }
+
+            fmt.Print(result.Script())
+
+            if applyDryRun {
+                return
+            }
+
+            if !applyYes {
+                fmt.Printf("\n确认在目标数据库 `%s` 上执行以上 %d 条语句？(y/N) ", targetDatabase, len(statements))
+
+                answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+
+                if !strings.EqualFold(strings.TrimSpace(answer), "y") {
+                    fmt.Println("已取消。")
+
+                    return
+                }
+            }
+
+            fmt.Println()
+
+            err = mysqldiff.Apply(cmd.Context(), conn, targetDatabase, statements, func(executed mysqldiff.Executed) {
+                status := "OK"
+
+                if executed.Err != nil {
+                    status = fmt.Sprintf("ERROR %s", executed.Err)
+                }
+
+                fmt.Printf("-- [%d/%d] %s (%s)\n%s\n", executed.Index, len(statements), status, executed.Elapsed.Round(time.Millisecond), executed.Statement)
+            })
+
+            cobra.CheckErr(err)
+
+            // 重新比对，确认目标数据库已与源数据库一致。
+            targetCatalog, err = loadConnCatalog(cmd, conn, targetDatabase, "目标", options.TableOptions)
+
+            cobra.CheckErr(err)
+
+            if remaining := mysqldiff.Compare(sourceCatalog, targetCatalog, options); len(remaining.Changes) > 0 {
+                fmt.Print("\n", remaining.Script())
+
+                cobra.CheckErr(fmt.Errorf("执行完成，但目标数据库与源数据库仍有 %d 处差异。", len(remaining.Changes)))
+            }
+
+            fmt.Println("\n执行完成，目标数据库已与源数据库一致。")
+        },
+    }
+)
+
+func init() {
+    applyFlags.register(applyCmd, "指定目标服务器。(格式: <user>:<password>@<host>:<port>)")
+    applyCmd.Flags().BoolVarP(&applyDryRun, "dry-run", "n", false, "只输出差异 SQL，不执行。")
+    applyCmd.Flags().BoolVarP(&applyYes, "yes", "y", false, "不再确认，直接执行。")
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+    "errors"
+    "fmt"
+    "os"
+    "regexp"
+    "strings"
+
+    "go-mysqldiff/pkg/mysqldiff"
+
+    "github.com/samber/lo"
+    "github.com/spf13/cobra"
+)
+
+// diffFlags 比对与 apply 共用的参数。
+type diffFlags struct {
+    source  string
+    target  string
+    db      string
+    comment bool
+    foreign bool
+    tidb    bool
+    rename  bool
+    hints   string
+
+    database       bool
+    createDatabase bool
+
+    autoIncrement string
+
+    includeTableOptions []string
+    excludeTableOptions []string
+}
+
+// register 注册参数，targetUsage 为 --target 的说明。
+func (f *diffFlags) register(cmd *cobra.Command, targetUsage string) {
+    cmd.Flags().StringVarP(&f.source, "source", "s", "", "指定源服务器、快照文件或 DDL 目录。(格式: <user>:<password>@<host>:<port>)")
+    cmd.Flags().StringVarP(&f.target, "target", "t", "", targetUsage)
+    cmd.Flags().StringVarP(&f.db, "db", "d", "", "指定数据库。(格式: <source_db>:<target_db>)")
+    cmd.Flags().BoolVarP(&f.comment, "comment", "c", false, "是否比对注释？")
+    cmd.Flags().BoolVarP(&f.foreign, "foreign", "f", false, "是否比对外键？")
+    cmd.Flags().BoolVarP(&f.tidb, "tidb", "i", false, "是否 TiDB ？")
+    cmd.Flags().BoolVarP(&f.rename, "rename", "r", true, "是否检测重命名？")
+    cmd.Flags().BoolVar(&f.database, "database", false, "是否比对库的字符集、排序规则、默认加密与只读？")
+    cmd.Flags().BoolVar(&f.createDatabase, "create-database", false, "目标数据库不存在时是否新建？")
+    cmd.Flags().StringVar(&f.hints, "hints", "", "指定重命名提示文件。")
+    cmd.Flags().StringVar(&f.autoIncrement, "auto-increment", "", "指定 AUTO_INCREMENT 的比对方式，默认不比对。(可选: create、raise)")
+    cmd.Flags().StringSliceVar(&f.includeTableOptions, "include-table-options", nil, "在默认扩展表选项之外增加比对的选项。(可选: encryption、data_directory、tablespace)")
+    cmd.Flags().StringSliceVar(&f.excludeTableOptions, "exclude-table-options", nil, "不比对的扩展表选项。(例如: row_format,key_block_size)")
+
+    cobra.CheckErr(cmd.MarkFlagRequired("db"))
+}
+
+// databases 补全源与目标（未指定时读取环境变量，未指定目标时与源相同），返回源库名与目标库名。
+func (f *diffFlags) databases() (string, string, error) {
+    if f.source == "" {
+        f.source = os.Getenv("MYSQLDIFF_SOURCE")
+    }
+    if f.target == "" {
+        f.target = os.Getenv("MYSQLDIFF_TARGET")
+    }
+
+    dbMatched, err := regexp.MatchString(DbPattern, f.db)
+
+    if err != nil {
+        return "", "", err
+    }
+
+    if !dbMatched {
+        return "", "", fmt.Errorf("数据库 `%s` 格式错误。(正确格式: <source_db>:<target_db>)", f.db)
+    }
+
+    if f.target == "" {
+        if isSnapshot(f.source) || isDDL(f.source) {
+            return "", "", fmt.Errorf("源为快照文件或 DDL 目录时必须指定目标服务器。")
+        }
+
+        f.target = f.source
+    }
+
+    databases := strings.Split(f.db, ":")
+
+    return databases[0], databases[1], nil
+}
+
+// options 返回比对选项。
+func (f *diffFlags) options() (mysqldiff.Options, error) {
+    if err := checkAutoIncrement(f.autoIncrement); err != nil {
+        return mysqldiff.Options{}, err
+    }
+
+    tableOptions, err := getTableOptions(f.includeTableOptions, f.excludeTableOptions)
+
+    if err != nil {
+        return mysqldiff.Options{}, err
+    }
+
+    renameHints, err := readHints(f.hints)
+
+    if err != nil {
+        return mysqldiff.Options{}, err
+    }
+
+    return mysqldiff.Options{
+        Comment: f.comment,
+        Foreign: f.foreign,
+        Tidb:    f.tidb,
+        Rename:  f.rename,
+        Hints:   renameHints,
+
+        Database:      f.database,
+        AutoIncrement: mysqldiff.AutoIncrement(f.autoIncrement),
+        TableOptions:  tableOptions,
+    }, nil
+}
+
+// targetCatalog 目标数据库不存在且指定了 --create-database 时，返回空的目标结构。
+func (f *diffFlags) targetCatalog(catalog *mysqldiff.Catalog, err error, database string) (*mysqldiff.Catalog, error) {
+    if errors.Is(err, mysqldiff.ErrSchemaNotFound) && f.createDatabase {
+        return mysqldiff.MissingCatalog(database), nil
+    }
+
+    return catalog, err
+}
+
+// checkAutoIncrement 检查 AUTO_INCREMENT 的比对方式。
+func checkAutoIncrement(autoIncrement string) error {
+    switch mysqldiff.AutoIncrement(autoIncrement) {
+    case mysqldiff.AutoIncrementNone, mysqldiff.AutoIncrementCreate, mysqldiff.AutoIncrementRaise:
+        return nil
+    }
+
+    return fmt.Errorf("AUTO_INCREMENT 比对方式 `%s` 错误。(可选: %s、%s)", autoIncrement, mysqldiff.AutoIncrementCreate, mysqldiff.AutoIncrementRaise)
+}
+
+// getTableOptions 返回比对的扩展表选项，即默认选项加上 include 再去掉 exclude，选项名不区分大小写，空格可写作下划线。
+func getTableOptions(include []string, exclude []string) ([]string, error) {
+    names := make(map[string]bool)
+
+    for _, name := range mysqldiff.DefaultTableOptions {
+        names[name] = true
+    }
+
+    // 同时指定时以 exclude 为准。
+    for i, options := range [][]string{include, exclude} {
+        for _, option := range options {
+            name := strings.ReplaceAll(strings.ToUpper(strings.TrimSpace(option)), "_DIRECTORY", " DIRECTORY")
+
+            if !lo.Contains(mysqldiff.TableOptions, name) {
+                return nil, fmt.Errorf("扩展表选项 `%s` 错误。(可选: %s)", option, strings.Join(mysqldiff.TableOptions, "、"))
+            }
+
+            names[name] = i == 0
+        }
+    }
+
+    return lo.Filter(mysqldiff.TableOptions, func(name string, _ int) bool {
+        return names[name]
+    }), nil
+}
+
+// readHints 读取重命名提示文件，未指定时返回空提示。
+func readHints(name string) (mysqldiff.Hints, error) {
+    if name == "" {
+        return mysqldiff.Hints{}, nil
+    }
+
+    return mysqldiff.ReadHintsFile(name)
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+    "slices"
+    "testing"
+
+    "go-mysqldiff/pkg/mysqldiff"
+
+    "github.com/spf13/cobra"
+)
+
+func TestDiffFlags(t *testing.T) {
+    for _, name := range []string{"root", "apply"} {
+        t.Run(name, func(t *testing.T) {
+            var flags diffFlags
+
+            cmd := &cobra.Command{Use: name}
+            flags.register(cmd, "target")
+
+            err := cmd.ParseFlags([]string{
+                "--source", "u:p@h:3306", "--db", "a:b", "--comment", "--rename=false", "--database",
+                "--auto-increment", "raise", "--include-table-options", "tablespace", "--exclude-table-options", "row_format",
+            })
+
+            if err != nil {
+                t.Fatal(err)
+            }
+
+            source, target, err := flags.databases()
+
+            if err != nil || source != "a" || target != "b" || flags.target != "u:p@h:3306" {
+                t.Fatalf("databases = %q %q %v, target %q", source, target, err, flags.target)
+            }
+
+            options, err := flags.options()
+
+            if err != nil {
+                t.Fatal(err)
+            }
+
+            if !options.Comment || options.Rename || !options.Database || options.AutoIncrement != mysqldiff.AutoIncrementRaise {
+                t.Fatalf("options = %+v", options)
+            }
+
+            if slices.Contains(options.TableOptions, mysqldiff.TableOptionRowFormat) || !slices.Contains(options.TableOptions, mysqldiff.TableOptionTablespace) {
+                t.Fatalf("table options = %v", options.TableOptions)
+            }
+        })
+    }
+}
+
+func TestDiffFlagsError(t *testing.T) {
+    tests := []struct {
+        name  string
+        flags diffFlags
+    }{
+        {"库名格式", diffFlags{source: "u:p@h:3306", db: "a"}},
+        {"AUTO_INCREMENT 比对方式", diffFlags{source: "u:p@h:3306", db: "a:b", autoIncrement: "lower"}},
+        {"扩展表选项", diffFlags{source: "u:p@h:3306", db: "a:b", includeTableOptions: []string{"engine"}}},
+        {"快照源缺少目标", diffFlags{source: "flags_test.go", db: "a:b"}},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            t.Setenv("MYSQLDIFF_TARGET", "")
+
+            _, _, err := tt.flags.databases()
+
+            if err == nil {
+                _, err = tt.flags.options()
+            }
+
+            if err == nil {
+                t.Fatal("no error")
+            }
+        })
+    }
+}
+
+func TestGetTableOptions(t *testing.T) {
+    tests := []struct {
+        name    string
+        include []string
+        exclude []string
+        want    []string
+    }{
+        {"默认", nil, nil, mysqldiff.DefaultTableOptions},
+        {"增加", []string{"Data_Directory", " encryption "}, nil, append(slices.Clone(mysqldiff.DefaultTableOptions), mysqldiff.TableOptionEncryption, mysqldiff.TableOptionDataDirectory)},
+        {"去掉", nil, []string{"row_format", "key_block_size", "stats_persistent", "stats_auto_recalc", "stats_sample_pages", "compression"}, []string{mysqldiff.TableOptionPackKeys}},
+        {"同时指定以 exclude 为准", []string{"tablespace"}, []string{"TABLESPACE", "pack_keys"}, mysqldiff.DefaultTableOptions[:len(mysqldiff.DefaultTableOptions)-1]},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, err := getTableOptions(tt.include, tt.exclude)
+
+            if err != nil {
+                t.Fatal(err)
+            }
+
+            // 按 TableOptions 的顺序返回。
+            want := slices.Clone(tt.want)
+            slices.SortFunc(want, func(a, b string) int {
+                return slices.Index(mysqldiff.TableOptions, a) - slices.Index(mysqldiff.TableOptions, b)
+            })
+
+            if !slices.Equal(got, want) {
+                t.Fatalf("table options = %v, want %v", got, want)
+            }
+        })
+    }
+}
+
+func TestDiffFlagsTargetCatalog(t *testing.T) {
+    catalog := mysqldiff.MissingCatalog("a")
+    notFound := schemaNotFoundError{role: "目标", database: "b"}
+
+    tests := []struct {
+        name           string
+        createDatabase bool
+        err            error
+        missing        bool
+    }{
+        {"数据库存在", true, nil, false},
+        {"数据库不存在", false, notFound, false},
+        {"数据库不存在时新建", true, notFound, true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            flags := diffFlags{createDatabase: tt.createDatabase}
+
+            got, err := flags.targetCatalog(catalog, tt.err, "b")
+
+            if tt.missing {
+                if err != nil || got.Schema.SchemaName != "b" {
+                    t.Fatalf("targetCatalog() = %+v, %v, want missing catalog `b`", got, err)
+                }
+
+                return
+            }
+
+            if got != catalog || err != tt.err {
+                t.Fatalf("targetCatalog() = %+v, %v, want %+v, %v", got, err, catalog, tt.err)
+            }
+        })
+    }
+}
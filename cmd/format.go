@@ -0,0 +1,42 @@
+package cmd
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+
+    "go-mysqldiff/pkg/mysqldiff"
+
+    "gopkg.in/yaml.v3"
+)
+
+// formats 支持的输出格式。
+var formats = []string{"sql", "json", "yaml", "markdown", "html"}
+
+// writeResult 按输出格式写入比对结果。
+func writeResult(w io.Writer, result *mysqldiff.Result, format string) error {
+    switch format {
+    case "json":
+        encoder := json.NewEncoder(w)
+        encoder.SetIndent("", "    ")
+
+        return encoder.Encode(result.Document())
+    case "yaml":
+        encoder := yaml.NewEncoder(w)
+        encoder.SetIndent(4)
+
+        if err := encoder.Encode(result.Document()); err != nil {
+            return err
+        }
+
+        return encoder.Close()
+    case "markdown":
+        return result.WriteMarkdown(w)
+    case "html":
+        return result.WriteHTML(w)
+    }
+
+    _, err := fmt.Fprint(w, result.Script())
+
+    return err
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+    "fmt"
+    "os"
+    "regexp"
+
+    "go-mysqldiff/pkg/mysqldiff"
+
+    "github.com/spf13/cobra"
+)
+
+var (
+    pullSource string
+    pullDb     string
+    pullOutput string
+    pullPrune  bool
+
+    pullCmd = &cobra.Command{
+        Use:   "pull",
+        Short: "将数据库结构导出为 DDL 目录。",
+        Run: func(cmd *cobra.Command, args []string) {
+            if pullSource == "" {
+                pullSource = os.Getenv("MYSQLDIFF_SOURCE")
+            }
+
+            dbMatched, err := regexp.MatchString(DbNamePattern, pullDb)
+
+            cobra.CheckErr(err)
+
+            if !dbMatched {
+                cobra.CheckErr(fmt.Errorf("数据库 `%s` 格式错误。(正确格式: <db>)", pullDb))
+            }
+
+            catalog, err := loadServerCatalog(cmd, pullSource, pullDb, "源", mysqldiff.DefaultTableOptions)
+
+            cobra.CheckErr(err)
+
+            pulled, err := mysqldiff.WriteSchemaFiles(pullOutput, mysqldiff.SchemaFiles(catalog), pullPrune)
+
+            for _, file := range pulled {
+                fmt.Printf("%s %s\n", file.Status, file.Path)
+            }
+
+            cobra.CheckErr(err)
+        },
+    }
+)
+
+func init() {
+    pullCmd.Flags().StringVarP(&pullSource, "source", "s", "", "指定服务器。(格式: <user>:<password>@<host>:<port>)")
+    pullCmd.Flags().StringVarP(&pullDb, "db", "d", "", "指定数据库。(格式: <db>)")
+    pullCmd.Flags().StringVarP(&pullOutput, "output", "o", "", "指定 DDL 目录。")
+    pullCmd.Flags().BoolVarP(&pullPrune, "prune", "p", false, "是否删除已不存在的对象的文件？")
+
+    cobra.CheckErr(pullCmd.MarkFlagRequired("db"))
+    cobra.CheckErr(pullCmd.MarkFlagRequired("output"))
+}
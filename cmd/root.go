@@ -1,24 +1,26 @@
 package cmd
 
 import (
+    "errors"
     "fmt"
     "os"
+    "path/filepath"
     "regexp"
-    "sort"
     "strconv"
     "strings"
-    "sync"
 
+    "go-mysqldiff/pkg/mysqldiff"
+
+    "github.com/samber/lo"
     "github.com/spf13/cobra"
-    "gorm.io/driver/mysql"
     "gorm.io/gorm"
-    "gorm.io/gorm/logger"
 )
 
 const (
-    Dsn         = "%s:%s@tcp(%s:%d)/information_schema?timeout=10s&parseTime=true&charset=%s"
     HostPattern = "^(.*)\\:(.*)\\@(.*)\\:(\\d+)$"
     DbPattern   = "^([A-Za-z0-9_\\-\\.]+)\\:([A-Za-z0-9_\\-\\.]+)$"
+
+    DbNamePattern = "^([A-Za-z0-9_\\-\\.]+)$"
 )
 
 func Execute() error {
@@ -28,219 +30,187 @@ func Execute() error {
 func init() {
     cobra.OnInitialize(initConfig)
 
-    rootCmd.Flags().StringVarP(&source, "source", "s", "", "指定源服务器。(格式: <user>:<password>@<host>:<port>)")
-    rootCmd.Flags().StringVarP(&target, "target", "t", "", "指定目标服务器。(格式: <user>:<password>@<host>:<port>)")
-    rootCmd.Flags().StringVarP(&db, "db", "d", "", "指定数据库。(格式: <source_db>:<target_db>)")
-    rootCmd.Flags().BoolVarP(&comment, "comment", "c", false, "是否比对注释？")
-    rootCmd.Flags().BoolVarP(&foreign, "foreign", "f", false, "是否比对外键？")
-    rootCmd.Flags().BoolVarP(&tidb, "tidb", "i", false, "是否 TiDB ？")
+    rootFlags.register(rootCmd, "指定目标服务器、快照文件或 DDL 目录。(格式: <user>:<password>@<host>:<port>)")
+    rootCmd.Flags().StringVar(&down, "down", "", "指定回滚脚本文件，格式与 --format 一致。")
+    rootCmd.Flags().StringVar(&format, "format", "sql", "指定输出格式。(可选: sql、json、yaml、markdown、html)")
 
     // cobra.CheckErr(rootCmd.MarkFlagRequired("source"))
-    cobra.CheckErr(rootCmd.MarkFlagRequired("db"))
 
     rootCmd.AddCommand(completionCmd)
+    rootCmd.AddCommand(snapshotCmd)
+    rootCmd.AddCommand(pullCmd)
+    rootCmd.AddCommand(applyCmd)
 }
 
 func initConfig() {
 }
 
 var (
-    wg   sync.WaitGroup
-    lock sync.Mutex
-    ch   = make(chan bool, 16)
+    rootFlags diffFlags
 
-    source  string
-    target  string
-    db      string
-    comment bool
-    foreign bool
-    tidb    bool
-
-    diffSqlKeys []string
-    diffSqlMap  = make(map[string]string)
+    format string
+    down   string
 
     rootCmd = &cobra.Command{
         Use:     "mysqldiff",
         Short:   "针对 MySQL 差异 SQL 工具。",
         Version: "v3.0.12",
         Run: func(cmd *cobra.Command, args []string) {
-            if source == "" {
-                source = os.Getenv("MYSQLDIFF_SOURCE")
-            }
-            if target == "" {
-                target = os.Getenv("MYSQLDIFF_TARGET")
-            }
-
-            sourceMatched, err1 := regexp.MatchString(HostPattern, source)
-            dbMatched, err3 := regexp.MatchString(DbPattern, db)
+            sourceDatabase, targetDatabase, err := rootFlags.databases()
 
-            cobra.CheckErr(err1)
-            cobra.CheckErr(err3)
+            cobra.CheckErr(err)
 
-            if !sourceMatched {
-                cobra.CheckErr(fmt.Errorf("源服务器 `%s` 格式错误。(正确格式: <user>:<password>@<host>:<port>)", source))
+            if !lo.Contains(formats, format) {
+                cobra.CheckErr(fmt.Errorf("输出格式 `%s` 错误。(可选: %s)", format, strings.Join(formats, "、")))
             }
 
-            if !dbMatched {
-                cobra.CheckErr(fmt.Errorf("数据库 `%s` 格式错误。(正确格式: <source_db>:<target_db>)", db))
-            }
+            options, err := rootFlags.options()
 
-            var (
-                sourceUser = strings.Split(source[0:strings.LastIndex(source, "@")], ":")
-                sourceHost = strings.Split(source[strings.LastIndex(source, "@")+1:], ":")
-                databases  = strings.Split(db, ":")
+            cobra.CheckErr(err)
 
-                err error
-            )
+            sourceCatalog, err := loadCatalog(cmd, rootFlags.source, sourceDatabase, "源", options.TableOptions)
 
-            sourceDbConfig := DbConfig{
-                User:     sourceUser[0],
-                Password: sourceUser[1],
-                Host:     sourceHost[0],
-                Charset:  "utf8",
-                Database: databases[0],
-            }
-            sourceDbConfig.Port, err = strconv.Atoi(sourceHost[1])
+            cobra.CheckErr(err)
+
+            targetCatalog, err := loadCatalog(cmd, rootFlags.target, targetDatabase, "目标", options.TableOptions)
+            targetCatalog, err = rootFlags.targetCatalog(targetCatalog, err, targetDatabase)
 
             cobra.CheckErr(err)
 
-            targetDbConfig := DbConfig{
-                Charset:  "utf8",
-                Database: databases[1],
+            result := mysqldiff.Compare(sourceCatalog, targetCatalog, options)
+
+            // Print Sql...
+            cobra.CheckErr(writeResult(os.Stdout, result, format))
+
+            // Print Down Sql...
+            if down != "" {
+                f, err := os.Create(down)
+
+                cobra.CheckErr(err)
+
+                defer f.Close()
+
+                cobra.CheckErr(writeResult(f, result.Down(), format))
             }
+        },
+    }
+)
 
-            sourceDb, err := gorm.Open(mysql.New(mysql.Config{
-                DSN: fmt.Sprintf(Dsn,
-                    sourceDbConfig.User, sourceDbConfig.Password,
-                    sourceDbConfig.Host, sourceDbConfig.Port,
-                    sourceDbConfig.Charset,
-                ),
-            }), &gorm.Config{
-                SkipDefaultTransaction: true,
-                DisableAutomaticPing:   true,
-                Logger:                 logger.Default.LogMode(logger.Silent),
-            })
+// isSnapshot 是否为快照文件。
+func isSnapshot(side string) bool {
+    if matched, _ := regexp.MatchString(HostPattern, side); matched {
+        return false
+    }
 
-            cobra.CheckErr(err)
+    info, err := os.Stat(side)
 
-            var targetDb = sourceDb
+    return err == nil && !info.IsDir() && !isSqlFile(side)
+}
 
-            if target != "" {
-                targetMatched, err2 := regexp.MatchString(HostPattern, target)
+// isDDL 是否为 DDL 目录或 .sql 文件。
+func isDDL(side string) bool {
+    if matched, _ := regexp.MatchString(HostPattern, side); matched {
+        return false
+    }
 
-                cobra.CheckErr(err2)
+    info, err := os.Stat(side)
 
-                if !targetMatched {
-                    cobra.CheckErr(fmt.Errorf("目标服务器 `%s` 格式错误。(正确格式: <user>:<password>@<host>:<port>)", target))
-                }
+    return err == nil && (info.IsDir() || isSqlFile(side))
+}
 
-                var targetUser = strings.Split(target[0:strings.LastIndex(target, "@")], ":")
-                var targetHost = strings.Split(target[strings.LastIndex(target, "@")+1:], ":")
+func isSqlFile(name string) bool {
+    return strings.EqualFold(filepath.Ext(name), ".sql")
+}
 
-                targetDbConfig.User = targetUser[0]
-                targetDbConfig.Password = targetUser[1]
-                targetDbConfig.Host = targetHost[0]
-                targetDbConfig.Port, err = strconv.Atoi(targetHost[1])
+// loadCatalog 从快照文件、DDL 目录或服务器读取数据库结构，role 为 "源" 或 "目标"。
+func loadCatalog(cmd *cobra.Command, side string, database string, role string, tableOptions []string) (*mysqldiff.Catalog, error) {
+    if isSnapshot(side) {
+        return mysqldiff.ReadSnapshotFile(side)
+    }
 
-                cobra.CheckErr(err)
+    if isDDL(side) {
+        return mysqldiff.ReadDDL(side, database)
+    }
 
-                targetDb, err = gorm.Open(mysql.New(mysql.Config{
-                    DSN: fmt.Sprintf(Dsn,
-                        targetDbConfig.User, targetDbConfig.Password,
-                        targetDbConfig.Host, targetDbConfig.Port,
-                        targetDbConfig.Charset,
-                    ),
-                }), &gorm.Config{
-                    SkipDefaultTransaction: true,
-                    DisableAutomaticPing:   true,
-                    Logger:                 logger.Default.LogMode(logger.Silent),
-                })
+    return loadServerCatalog(cmd, side, database, role, tableOptions)
+}
 
-                cobra.CheckErr(err)
-            }
+// loadServerCatalog 从服务器读取数据库结构。
+func loadServerCatalog(cmd *cobra.Command, server string, database string, role string, tableOptions []string) (*mysqldiff.Catalog, error) {
+    conn, err := openServer(server, database, role)
 
-            var (
-                sourceSchema Schema
-                targetSchema Schema
-            )
+    if err != nil {
+        return nil, err
+    }
 
-            sourceSchemaResult := sourceDb.Table("SCHEMATA").Limit(1).Find(
-                &sourceSchema,
-                "`SCHEMA_NAME` = ?", sourceDbConfig.Database,
-            )
+    return loadConnCatalog(cmd, conn, database, role, tableOptions)
+}
 
-            targetSchemaResult := targetDb.Table("SCHEMATA").Limit(1).Find(
-                &targetSchema,
-                "`SCHEMA_NAME` = ?", targetDbConfig.Database,
-            )
+// openServer 连接服务器。
+func openServer(server string, database string, role string) (*gorm.DB, error) {
+    dbConfig, err := parseServer(server, database)
 
-            if sourceSchemaResult.RowsAffected <= 0 {
-                cobra.CheckErr(fmt.Errorf("源数据库 `%s` 不存在。", databases[0]))
-            }
+    if err != nil {
+        return nil, fmt.Errorf("%s服务器 %w", role, err)
+    }
 
-            if targetSchemaResult.RowsAffected <= 0 {
-                cobra.CheckErr(fmt.Errorf("目标数据库 `%s` 不存在。", databases[1]))
-            }
+    return mysqldiff.Open(dbConfig)
+}
 
-            var (
-                sourceTableData []Table
-                targetTableData []Table
-            )
-
-            sourceDb.Table("TABLES").Order("`TABLE_NAME` ASC").Find(
-                &sourceTableData,
-                "`TABLE_SCHEMA` = ?", sourceDbConfig.Database,
-            )
-            targetDb.Table("TABLES").Order("`TABLE_NAME` ASC").Find(
-                &targetTableData,
-                "`TABLE_SCHEMA` = ?", targetDbConfig.Database,
-            )
-
-            sourceTableMap := make(map[string]Table)
-            targetTableMap := make(map[string]Table)
-
-            for _, table := range sourceTableData {
-                sourceTableMap[table.TableName] = table
-            }
+// loadConnCatalog 从已连接的服务器读取数据库结构，tableOptions 包含 DATA DIRECTORY 或 TABLESPACE 时逐表读取。
+func loadConnCatalog(cmd *cobra.Command, conn *gorm.DB, database string, role string, tableOptions []string) (*mysqldiff.Catalog, error) {
+    catalog, err := mysqldiff.Load(cmd.Context(), conn, database)
 
-            for _, table := range targetTableData {
-                targetTableMap[table.TableName] = table
-            }
+    if errors.Is(err, mysqldiff.ErrSchemaNotFound) {
+        return nil, schemaNotFoundError{role: role, database: database}
+    }
 
-            // DROP TABLE Or DROP VIEW...
-            drop(sourceTableMap, targetTableData)
+    if err != nil {
+        return nil, err
+    }
 
-            defer close(ch)
+    return catalog, catalog.LoadStorage(conn, tableOptions)
+}
 
-            for _, sourceTable := range sourceTableData {
-                wg.Add(1)
-                go diff(sourceDbConfig, targetDbConfig, sourceDb, targetDb, sourceSchema, sourceTable, targetTableMap)
-            }
+// schemaNotFoundError 数据库不存在，可用 mysqldiff.ErrSchemaNotFound 判断。
+type schemaNotFoundError struct {
+    role     string
+    database string
+}
 
-            wg.Wait()
+func (e schemaNotFoundError) Error() string {
+    return fmt.Sprintf("%s数据库 `%s` 不存在。", e.role, e.database)
+}
 
-            // Print Sql...
-            if len(diffSqlKeys) > 0 && len(diffSqlMap) > 0 {
-                fmt.Println(fmt.Sprintf("SET NAMES %s;\n", sourceSchema.DefaultCharacterSetName))
-                fmt.Println("SET FOREIGN_KEY_CHECKS=0;")
-                fmt.Println()
-
-                sort.Strings(diffSqlKeys)
-
-                for k, diffSqlKey := range diffSqlKeys {
-                    if diffSql, ok := diffSqlMap[diffSqlKey]; ok {
-                        if k < len(diffSqlKeys)-1 {
-                            fmt.Println(diffSql)
-                            fmt.Println()
-                        } else {
-                            fmt.Println(diffSql)
-                        }
-                    }
-                }
-
-                fmt.Println()
-                fmt.Println("SET FOREIGN_KEY_CHECKS=1;")
-            }
-        },
+func (e schemaNotFoundError) Unwrap() error {
+    return mysqldiff.ErrSchemaNotFound
+}
+
+// parseServer 解析 <user>:<password>@<host>:<port> 格式的服务器。
+func parseServer(server string, database string) (mysqldiff.DbConfig, error) {
+    matched, err := regexp.MatchString(HostPattern, server)
+
+    if err != nil {
+        return mysqldiff.DbConfig{}, err
     }
-)
+
+    if !matched {
+        return mysqldiff.DbConfig{}, fmt.Errorf("`%s` 格式错误。(正确格式: <user>:<password>@<host>:<port>)", server)
+    }
+
+    var (
+        user = strings.Split(server[0:strings.LastIndex(server, "@")], ":")
+        host = strings.Split(server[strings.LastIndex(server, "@")+1:], ":")
+    )
+
+    config := mysqldiff.DbConfig{
+        User:     user[0],
+        Password: user[1],
+        Host:     host[0],
+        Charset:  "utf8",
+        Database: database,
+    }
+    config.Port, err = strconv.Atoi(host[1])
+
+    return config, err
+}
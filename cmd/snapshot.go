@@ -0,0 +1,61 @@
+package cmd
+
+import (
+    "fmt"
+    "os"
+    "regexp"
+
+    "go-mysqldiff/pkg/mysqldiff"
+
+    "github.com/spf13/cobra"
+)
+
+var (
+    snapshotSource string
+    snapshotDb     string
+    snapshotOutput string
+
+    snapshotCmd = &cobra.Command{
+        Use:   "snapshot",
+        Short: "导出数据库结构快照。",
+        Run: func(cmd *cobra.Command, args []string) {
+            if snapshotSource == "" {
+                snapshotSource = os.Getenv("MYSQLDIFF_SOURCE")
+            }
+
+            dbMatched, err := regexp.MatchString(DbNamePattern, snapshotDb)
+
+            cobra.CheckErr(err)
+
+            if !dbMatched {
+                cobra.CheckErr(fmt.Errorf("数据库 `%s` 格式错误。(正确格式: <db>)", snapshotDb))
+            }
+
+            catalog, err := loadServerCatalog(cmd, snapshotSource, snapshotDb, "源", mysqldiff.TableOptions)
+
+            cobra.CheckErr(err)
+
+            if snapshotOutput == "" || snapshotOutput == "-" {
+                cobra.CheckErr(mysqldiff.WriteSnapshot(os.Stdout, catalog))
+
+                return
+            }
+
+            f, err := os.Create(snapshotOutput)
+
+            cobra.CheckErr(err)
+
+            defer f.Close()
+
+            cobra.CheckErr(mysqldiff.WriteSnapshot(f, catalog))
+        },
+    }
+)
+
+func init() {
+    snapshotCmd.Flags().StringVarP(&snapshotSource, "source", "s", "", "指定服务器。(格式: <user>:<password>@<host>:<port>)")
+    snapshotCmd.Flags().StringVarP(&snapshotDb, "db", "d", "", "指定数据库。(格式: <db>)")
+    snapshotCmd.Flags().StringVarP(&snapshotOutput, "output", "o", "", "指定快照文件，默认输出到标准输出。")
+
+    cobra.CheckErr(snapshotCmd.MarkFlagRequired("db"))
+}
@@ -0,0 +1,76 @@
+package mysqldiff
+
+import (
+    "context"
+    "fmt"
+    "strings"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+// Executed 单条语句的执行结果。
+type Executed struct {
+    Index     int // 从 1 开始
+    Statement string
+    Elapsed   time.Duration
+    Err       error
+}
+
+// Apply 在目标数据库上逐条执行语句，db 为 Open 返回的连接，遇到错误立即停止；每条语句执行后调用 callback。
+//
+// 全部语句在同一连接上执行，以保证 SET NAMES 与 SET FOREIGN_KEY_CHECKS 生效。
+func Apply(ctx context.Context, db *gorm.DB, database string, statements []string, callback func(Executed)) error {
+    sqlDb, err := db.DB()
+
+    if err != nil {
+        return err
+    }
+
+    conn, err := sqlDb.Conn(ctx)
+
+    if err != nil {
+        return err
+    }
+
+    defer conn.Close()
+
+    var count int64
+
+    if err := db.WithContext(ctx).Table("SCHEMATA").Where("`SCHEMA_NAME` = ?", database).Count(&count).Error; err != nil {
+        return err
+    }
+
+    // 目标数据库不存在时，由差异中的 CREATE DATABASE 之后的 USE 切换。
+    if count > 0 {
+        if _, err := conn.ExecContext(ctx, fmt.Sprintf("USE `%s`", getQuotedName(database))); err != nil {
+            return err
+        }
+    }
+
+    // 连接归还连接池前切换回 information_schema，以免影响之后的 Load。
+    defer conn.ExecContext(context.Background(), "USE `information_schema`")
+
+    for i, statement := range statements {
+        start := time.Now()
+
+        _, err := conn.ExecContext(ctx, strings.TrimSuffix(strings.TrimSpace(statement), ";"))
+
+        executed := Executed{
+            Index:     i + 1,
+            Statement: statement,
+            Elapsed:   time.Since(start),
+            Err:       err,
+        }
+
+        if callback != nil {
+            callback(executed)
+        }
+
+        if err != nil {
+            return fmt.Errorf("第 %d 条语句执行失败：%w", executed.Index, err)
+        }
+    }
+
+    return nil
+}
@@ -0,0 +1,170 @@
+package mysqldiff
+
+import (
+    "context"
+    "database/sql"
+    "database/sql/driver"
+    "errors"
+    "io"
+    "slices"
+    "strings"
+    "sync"
+    "testing"
+
+    "gorm.io/driver/mysql"
+    "gorm.io/gorm"
+    "gorm.io/gorm/logger"
+)
+
+// fakeDriver 记录执行的语句，count 为查询 SCHEMATA 返回的行数，执行 fail 时返回错误。
+type fakeDriver struct {
+    mu       sync.Mutex
+    executed []string
+    count    int64
+    fail     string
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+    return &fakeConn{d: d}, nil
+}
+
+type fakeConn struct {
+    d *fakeDriver
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+    return nil, errors.New("not supported")
+}
+
+func (c *fakeConn) Close() error {
+    return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+    return nil, errors.New("not supported")
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+    c.d.mu.Lock()
+    defer c.d.mu.Unlock()
+
+    c.d.executed = append(c.d.executed, query)
+
+    if query == c.d.fail {
+        return nil, errors.New("failed")
+    }
+
+    return driver.RowsAffected(0), nil
+}
+
+func (c *fakeConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+    return &fakeRows{values: []driver.Value{c.d.count}}, nil
+}
+
+type fakeRows struct {
+    values []driver.Value
+    read   bool
+}
+
+func (r *fakeRows) Columns() []string {
+    return []string{"count"}
+}
+
+func (r *fakeRows) Close() error {
+    return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+    if r.read {
+        return io.EOF
+    }
+
+    r.read = true
+    copy(dest, r.values)
+
+    return nil
+}
+
+// openFake 返回使用 fakeDriver 的连接。
+func openFake(t *testing.T, d *fakeDriver) *gorm.DB {
+    t.Helper()
+
+    name := "fake_" + strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+    sql.Register(name, d)
+
+    sqlDb, err := sql.Open(name, "")
+
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    t.Cleanup(func() { sqlDb.Close() })
+
+    db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDb, SkipInitializeWithVersion: true}), &gorm.Config{
+        SkipDefaultTransaction: true,
+        DisableAutomaticPing:   true,
+        Logger:                 logger.Default.LogMode(logger.Silent),
+    })
+
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return db
+}
+
+func TestApply(t *testing.T) {
+    tests := []struct {
+        name     string
+        count    int64
+        fail     string
+        executed []string
+        indexes  []int
+        err      string
+    }{
+        {
+            name:     "逐条执行",
+            count:    1,
+            executed: []string{"USE `db`", "SET NAMES utf8mb4", "ALTER TABLE `t` ADD COLUMN `a` int", "DROP TABLE `b`", "USE `information_schema`"},
+            indexes:  []int{1, 2, 3},
+        },
+        {
+            name:     "库不存在时不切换",
+            count:    0,
+            executed: []string{"SET NAMES utf8mb4", "ALTER TABLE `t` ADD COLUMN `a` int", "DROP TABLE `b`", "USE `information_schema`"},
+            indexes:  []int{1, 2, 3},
+        },
+        {
+            name:     "遇到错误立即停止",
+            count:    1,
+            fail:     "ALTER TABLE `t` ADD COLUMN `a` int",
+            executed: []string{"USE `db`", "SET NAMES utf8mb4", "ALTER TABLE `t` ADD COLUMN `a` int", "USE `information_schema`"},
+            indexes:  []int{1, 2},
+            err:      "第 2 条语句执行失败：failed",
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            d := &fakeDriver{count: tt.count, fail: tt.fail}
+
+            var indexes []int
+
+            err := Apply(context.Background(), openFake(t, d), "db", []string{"SET NAMES utf8mb4;", "  ALTER TABLE `t` ADD COLUMN `a` int;\n", "DROP TABLE `b`"}, func(executed Executed) {
+                indexes = append(indexes, executed.Index)
+            })
+
+            if tt.err == "" && err != nil || tt.err != "" && (err == nil || err.Error() != tt.err) {
+                t.Fatalf("err = %v, want %q", err, tt.err)
+            }
+
+            if !slices.Equal(d.executed, tt.executed) {
+                t.Fatalf("executed = %q, want %q", d.executed, tt.executed)
+            }
+
+            if !slices.Equal(indexes, tt.indexes) {
+                t.Fatalf("callback indexes = %v, want %v", indexes, tt.indexes)
+            }
+        })
+    }
+}
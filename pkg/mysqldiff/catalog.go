@@ -0,0 +1,414 @@
+package mysqldiff
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "fmt"
+    "regexp"
+    "strings"
+
+    "github.com/samber/lo"
+    "gorm.io/gorm"
+)
+
+// ErrSchemaNotFound 数据库不存在。
+var ErrSchemaNotFound = errors.New("数据库不存在。")
+
+// SHOW CREATE TABLE 中指定了解析器的全文索引、数据目录、表空间与分区定义。
+var (
+    fulltextParserPattern = regexp.MustCompile("(?m)^\\s*FULLTEXT KEY `((?:[^`]|``)+)` .*WITH PARSER `([^`]+)`")
+    dataDirectoryPattern  = regexp.MustCompile("DATA DIRECTORY='((?:[^'\\\\]|\\\\.|'')*)'")
+    tablespacePattern     = regexp.MustCompile("TABLESPACE `((?:[^`]|``)+)`")
+    partitionByPattern    = regexp.MustCompile("(?m)^(?:/\\*!\\d+ )?PARTITION BY ")
+)
+
+// Catalog 一个数据库在 information_schema 中的全部结构信息。
+type Catalog struct {
+    Schema                 Schema
+    Tables                 []Table
+    Columns                []Column
+    Statistics             []Statistic
+    Views                  []View
+    TableConstraints       []TableConstraints
+    CheckConstraints       []CheckConstraints
+    ReferentialConstraints []ReferentialConstraints
+    KeyColumnUsages        []KeyColumnUsage
+    Triggers               []Trigger
+    Routines               []Routine
+    Parameters             []Parameter
+    Events                 []Event
+    Partitions             []Partition
+
+    tables      map[string]Table
+    columns     map[string][]Column
+    indexes     map[string][]Index
+    foreignKeys map[string][]ForeignKey
+    checks      map[string][]Check
+    views       map[string]View
+    triggers    map[string]Trigger
+    routines    map[string]StoredRoutine
+    events      map[string]Event
+    partitions  map[string][]Partition
+
+    missing bool
+}
+
+// Load 读取数据库结构，每张 information_schema 表只查询一次。
+func Load(ctx context.Context, db *gorm.DB, name string) (*Catalog, error) {
+    db = db.WithContext(ctx)
+
+    c := &Catalog{}
+
+    schemaResult := db.Table("SCHEMATA").Limit(1).Find(
+        &c.Schema,
+        "`SCHEMA_NAME` = ?", name,
+    )
+
+    if schemaResult.Error != nil {
+        return nil, schemaResult.Error
+    }
+
+    if schemaResult.RowsAffected <= 0 {
+        return nil, fmt.Errorf("`%s` %w", name, ErrSchemaNotFound)
+    }
+
+    queries := []struct {
+        table string
+        order string
+        dest  interface{}
+        query string
+    }{
+        {"TABLES", "`TABLE_NAME` ASC", &c.Tables, "`TABLE_SCHEMA` = ?"},
+        {"COLUMNS", "`TABLE_NAME` ASC, `ORDINAL_POSITION` ASC", &c.Columns, "`TABLE_SCHEMA` = ?"},
+        {"STATISTICS", "", &c.Statistics, "`TABLE_SCHEMA` = ?"},
+        {"VIEWS", "`TABLE_NAME` ASC", &c.Views, "`TABLE_SCHEMA` = ?"},
+        {"TABLE_CONSTRAINTS", "`TABLE_NAME` ASC", &c.TableConstraints, "`TABLE_SCHEMA` = ?"},
+        {"REFERENTIAL_CONSTRAINTS", "`TABLE_NAME` ASC", &c.ReferentialConstraints, "`CONSTRAINT_SCHEMA` = ?"},
+        {"KEY_COLUMN_USAGE", "`TABLE_NAME` ASC, `CONSTRAINT_NAME` ASC, `POSITION_IN_UNIQUE_CONSTRAINT` ASC", &c.KeyColumnUsages, "`TABLE_SCHEMA` = ? AND `REFERENCED_TABLE_NAME` IS NOT NULL"},
+        {"ROUTINES", "`ROUTINE_TYPE` ASC, `ROUTINE_NAME` ASC", &c.Routines, "`ROUTINE_SCHEMA` = ?"},
+        {"PARAMETERS", "`ROUTINE_TYPE` ASC, `SPECIFIC_NAME` ASC, `ORDINAL_POSITION` ASC", &c.Parameters, "`SPECIFIC_SCHEMA` = ?"},
+        {"PARTITIONS", "`TABLE_NAME` ASC, `PARTITION_ORDINAL_POSITION` ASC, `SUBPARTITION_ORDINAL_POSITION` ASC", &c.Partitions, "`TABLE_SCHEMA` = ? AND `PARTITION_NAME` IS NOT NULL"},
+        {"EVENTS", "`EVENT_NAME` ASC", &c.Events, "`EVENT_SCHEMA` = ?"},
+        {"TRIGGERS", "`EVENT_OBJECT_TABLE` ASC, `ACTION_TIMING` ASC, `EVENT_MANIPULATION` ASC, `ACTION_ORDER` ASC", &c.Triggers, "`TRIGGER_SCHEMA` = ?"},
+    }
+
+    for _, q := range queries {
+        tx := db.Table(q.table)
+
+        if q.order != "" {
+            tx = tx.Order(q.order)
+        }
+
+        if err := tx.Find(q.dest, q.query, name).Error; err != nil {
+            return nil, err
+        }
+    }
+
+    // MySQL 8.0.16 以前没有 CHECK_CONSTRAINTS。
+    if ok, err := hasTable(db, "CHECK_CONSTRAINTS"); err != nil {
+        return nil, err
+    } else if ok {
+        if err := db.Table("CHECK_CONSTRAINTS").Order("`CONSTRAINT_NAME` ASC").Find(&c.CheckConstraints, "`CONSTRAINT_SCHEMA` = ?", name).Error; err != nil {
+            return nil, err
+        }
+    }
+
+    if err := c.loadParsers(db, name); err != nil {
+        return nil, err
+    }
+
+    if err := c.loadReadOnly(db, name); err != nil {
+        return nil, err
+    }
+
+    c.build()
+
+    return c, nil
+}
+
+// MissingCatalog 返回不存在的数据库的空结构，作为目标数据库比对时生成 CREATE DATABASE。
+func MissingCatalog(name string) *Catalog {
+    return &Catalog{Schema: Schema{CatalogName: "def", SchemaName: name}, missing: true}
+}
+
+// loadReadOnly 读取库是否只读，服务器没有 SCHEMATA_EXTENSIONS 时（MySQL 8.0.22 以前、TiDB）跳过。
+func (c *Catalog) loadReadOnly(db *gorm.DB, name string) error {
+    ok, err := hasTable(db, "SCHEMATA_EXTENSIONS")
+
+    if err != nil || !ok {
+        return err
+    }
+
+    var options sql.NullString
+
+    err = db.Table("SCHEMATA_EXTENSIONS").Select("`OPTIONS`").Where("`SCHEMA_NAME` = ?", name).Row().Scan(&options)
+
+    if err != nil {
+        return err
+    }
+
+    c.Schema.ReadOnly = strings.Contains(options.String, "READ ONLY=1")
+
+    return nil
+}
+
+// hasTable information_schema 中是否有该表，用于读取新版本才有的表。
+func hasTable(db *gorm.DB, name string) (bool, error) {
+    var count int64
+
+    err := db.Table("TABLES").Where("`TABLE_SCHEMA` = ? AND `TABLE_NAME` = ?", "information_schema", name).Count(&count).Error
+
+    return count > 0, err
+}
+
+// loadParsers 读取全文索引的解析器，information_schema 中没有，只能从 SHOW CREATE TABLE 中读取。
+func (c *Catalog) loadParsers(db *gorm.DB, name string) error {
+    var (
+        tableNames []string
+        parsers    = make(map[string]string)
+    )
+
+    for _, statistic := range c.Statistics {
+        if statistic.IndexType == "FULLTEXT" && !lo.Contains(tableNames, statistic.TableName) {
+            tableNames = append(tableNames, statistic.TableName)
+        }
+    }
+
+    for _, tableName := range tableNames {
+        createTable, err := showCreateTable(db, name, tableName)
+
+        if err != nil {
+            return err
+        }
+
+        for _, matches := range fulltextParserPattern.FindAllStringSubmatch(createTable, -1) {
+            parsers[tableName+"."+strings.ReplaceAll(matches[1], "``", "`")] = strings.ToLower(matches[2])
+        }
+    }
+
+    for i, statistic := range c.Statistics {
+        c.Statistics[i].Parser = parsers[statistic.TableName+"."+statistic.IndexName]
+    }
+
+    return nil
+}
+
+// LoadStorage 读取表的 DATA DIRECTORY 与 TABLESPACE，information_schema 中没有，只能逐表执行 SHOW CREATE TABLE，
+// 因此仅在 tableOptions 包含这两项时读取。
+func (c *Catalog) LoadStorage(db *gorm.DB, tableOptions []string) error {
+    if !lo.Contains(tableOptions, TableOptionDataDirectory) && !lo.Contains(tableOptions, TableOptionTablespace) {
+        return nil
+    }
+
+    for i, table := range c.Tables {
+        if table.TableType != "BASE TABLE" {
+            continue
+        }
+
+        createTable, err := showCreateTable(db, c.Schema.SchemaName, table.TableName)
+
+        if err != nil {
+            return err
+        }
+
+        // 分区定义中也可以指定 TABLESPACE 与 DATA DIRECTORY。
+        if index := partitionByPattern.FindStringIndex(createTable); index != nil {
+            createTable = createTable[:index[0]]
+        }
+
+        if matches := dataDirectoryPattern.FindStringSubmatch(createTable); matches != nil {
+            c.Tables[i].DataDirectory = strings.NewReplacer("''", "'", "\\\\", "\\", "\\'", "'").Replace(matches[1])
+        }
+
+        if matches := tablespacePattern.FindStringSubmatch(createTable); matches != nil {
+            c.Tables[i].Tablespace = strings.ReplaceAll(matches[1], "``", "`")
+        }
+    }
+
+    return nil
+}
+
+// showCreateTable 返回 SHOW CREATE TABLE 的结果。
+func showCreateTable(db *gorm.DB, name string, tableName string) (string, error) {
+    var table, createTable string
+
+    err := db.Raw(fmt.Sprintf("SHOW CREATE TABLE `%s`.`%s`", getQuotedName(name), getQuotedName(tableName))).Row().Scan(&table, &createTable)
+
+    return createTable, err
+}
+
+// build 按表名建立内存索引。
+func (c *Catalog) build() {
+    c.tables = make(map[string]Table)
+    c.columns = make(map[string][]Column)
+    c.indexes = make(map[string][]Index)
+    c.foreignKeys = make(map[string][]ForeignKey)
+    c.checks = make(map[string][]Check)
+    c.views = make(map[string]View)
+    c.triggers = make(map[string]Trigger)
+    c.routines = make(map[string]StoredRoutine)
+    c.events = make(map[string]Event)
+    c.partitions = make(map[string][]Partition)
+
+    for _, table := range c.Tables {
+        c.tables[table.TableName] = table
+    }
+
+    for _, column := range c.Columns {
+        c.columns[column.TableName] = append(c.columns[column.TableName], column)
+    }
+
+    statistics := make(map[string][]Statistic)
+
+    for _, statistic := range c.Statistics {
+        statistics[statistic.TableName] = append(statistics[statistic.TableName], statistic)
+    }
+
+    for tableName, tableStatistics := range statistics {
+        c.indexes[tableName] = getIndexes(tableStatistics)
+    }
+
+    for _, partition := range c.Partitions {
+        c.partitions[partition.TableName] = append(c.partitions[partition.TableName], partition)
+    }
+
+    for _, view := range c.Views {
+        c.views[view.TableName] = view
+    }
+
+    for _, trigger := range c.Triggers {
+        c.triggers[trigger.TriggerName] = trigger
+    }
+
+    parameters := make(map[string][]Parameter)
+
+    for _, parameter := range c.Parameters {
+        // 函数的返回值 ORDINAL_POSITION 为 0。
+        if parameter.OrdinalPosition > 0 {
+            key := getRoutineKey(parameter.RoutineType, parameter.SpecificName)
+            parameters[key] = append(parameters[key], parameter)
+        }
+    }
+
+    for _, routine := range c.Routines {
+        key := getRoutineKey(routine.RoutineType, routine.RoutineName)
+        c.routines[key] = StoredRoutine{Routine: routine, Parameters: parameters[key]}
+    }
+
+    for _, event := range c.Events {
+        c.events[event.EventName] = event
+    }
+
+    referentialConstraints := make(map[string]ReferentialConstraints)
+    keyColumnUsages := make(map[string][]KeyColumnUsage)
+
+    for _, referentialConstraint := range c.ReferentialConstraints {
+        referentialConstraints[referentialConstraint.ConstraintName] = referentialConstraint
+    }
+
+    for _, keyColumnUsage := range c.KeyColumnUsages {
+        key := keyColumnUsage.TableName + "." + keyColumnUsage.ConstraintName
+        keyColumnUsages[key] = append(keyColumnUsages[key], keyColumnUsage)
+    }
+
+    // 忽略缺少引用信息的外键约束。
+    for _, tableConstraint := range c.TableConstraints {
+        if tableConstraint.ConstraintType != "FOREIGN KEY" {
+            continue
+        }
+
+        referentialConstraint, ok := referentialConstraints[tableConstraint.ConstraintName]
+        key := tableConstraint.TableName + "." + tableConstraint.ConstraintName
+
+        if !ok || len(keyColumnUsages[key]) <= 0 {
+            continue
+        }
+
+        c.foreignKeys[tableConstraint.TableName] = append(c.foreignKeys[tableConstraint.TableName], ForeignKey{
+            Constraint:      tableConstraint,
+            Referential:     referentialConstraint,
+            KeyColumnUsages: keyColumnUsages[key],
+        })
+    }
+
+    // CHECK 约束名在库中唯一。
+    checkClauses := make(map[string]string)
+
+    for _, checkConstraint := range c.CheckConstraints {
+        checkClauses[checkConstraint.ConstraintName] = checkConstraint.CheckClause
+    }
+
+    for _, tableConstraint := range c.TableConstraints {
+        if checkClause, ok := checkClauses[tableConstraint.ConstraintName]; ok && tableConstraint.ConstraintType == "CHECK" {
+            c.checks[tableConstraint.TableName] = append(c.checks[tableConstraint.TableName], Check{
+                Constraint:  tableConstraint,
+                CheckClause: checkClause,
+            })
+        }
+    }
+}
+
+// Table 返回表（或视图）。
+func (c *Catalog) Table(name string) (Table, bool) {
+    table, ok := c.tables[name]
+
+    return table, ok
+}
+
+// TableColumns 返回表的列，按 ORDINAL_POSITION 排序。
+func (c *Catalog) TableColumns(name string) []Column {
+    return c.columns[name]
+}
+
+// TableIndexes 返回表的索引。
+func (c *Catalog) TableIndexes(name string) []Index {
+    return c.indexes[name]
+}
+
+// TableForeignKeys 返回表的外键。
+func (c *Catalog) TableForeignKeys(name string) []ForeignKey {
+    return c.foreignKeys[name]
+}
+
+// TableChecks 返回表的 CHECK 约束。
+func (c *Catalog) TableChecks(name string) []Check {
+    return c.checks[name]
+}
+
+// TablePartitions 返回表的分区，有子分区时每个子分区一行，未分区时返回空。
+func (c *Catalog) TablePartitions(name string) []Partition {
+    return c.partitions[name]
+}
+
+// View 返回视图，视图定义中已去掉库名前缀。
+func (c *Catalog) View(name string) (View, bool) {
+    view, ok := c.views[name]
+
+    if ok {
+        view.ViewDefinition = getViewDefinition(view, c.Schema.SchemaName)
+    }
+
+    return view, ok
+}
+
+// Trigger 返回触发器。
+func (c *Catalog) Trigger(name string) (Trigger, bool) {
+    trigger, ok := c.triggers[name]
+
+    return trigger, ok
+}
+
+// Routine 返回存储过程（PROCEDURE）或函数（FUNCTION）。
+func (c *Catalog) Routine(routineType string, name string) (StoredRoutine, bool) {
+    routine, ok := c.routines[getRoutineKey(routineType, name)]
+
+    return routine, ok
+}
+
+// Event 返回事件。
+func (c *Catalog) Event(name string) (Event, bool) {
+    event, ok := c.events[name]
+
+    return event, ok
+}
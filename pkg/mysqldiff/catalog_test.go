@@ -0,0 +1,93 @@
+package mysqldiff
+
+import (
+    "slices"
+    "testing"
+)
+
+func TestCatalogBuild(t *testing.T) {
+    // 与 Load 一次读取的行相同，多张表的行交错排列。
+    c := &Catalog{
+        Schema: Schema{SchemaName: "db"},
+        Tables: []Table{{TableName: "a"}, {TableName: "b"}},
+        Columns: []Column{
+            {TableName: "a", ColumnName: "id", OrdinalPosition: 1},
+            {TableName: "b", ColumnName: "id", OrdinalPosition: 1},
+            {TableName: "a", ColumnName: "b_id", OrdinalPosition: 2},
+        },
+        Statistics: []Statistic{
+            {TableName: "a", IndexName: "idx", SeqInIndex: 2, ColumnName: "id"},
+            {TableName: "b", IndexName: "PRIMARY", SeqInIndex: 1, ColumnName: "id"},
+            {TableName: "a", IndexName: "idx", SeqInIndex: 1, ColumnName: "b_id"},
+            {TableName: "a", IndexName: "PRIMARY", SeqInIndex: 1, ColumnName: "id"},
+        },
+        TableConstraints: []TableConstraints{
+            {TableName: "a", ConstraintName: "fk", ConstraintType: "FOREIGN KEY"},
+            {TableName: "a", ConstraintName: "fk_missing", ConstraintType: "FOREIGN KEY"},
+            {TableName: "a", ConstraintName: "chk", ConstraintType: "CHECK", ENFORCED: "YES"},
+        },
+        ReferentialConstraints: []ReferentialConstraints{
+            {TableName: "a", ConstraintName: "fk", ReferencedTableName: "b"},
+        },
+        KeyColumnUsages: []KeyColumnUsage{
+            {TableName: "a", ConstraintName: "fk", ColumnName: "b_id", ReferencedTableName: "b", ReferencedColumnName: "id"},
+        },
+        CheckConstraints: []CheckConstraints{{ConstraintName: "chk", CheckClause: "(`id` > 0)"}},
+        Views:            []View{{TableName: "v", ViewDefinition: "select `db`.`a`.`id` AS `id` from `db`.`a`"}},
+        Routines:         []Routine{{RoutineName: "f", RoutineType: "FUNCTION"}, {RoutineName: "f", RoutineType: "PROCEDURE"}},
+        Parameters: []Parameter{
+            {SpecificName: "f", RoutineType: "FUNCTION", OrdinalPosition: 0, DataType: "int"},
+            {SpecificName: "f", RoutineType: "FUNCTION", OrdinalPosition: 1, DataType: "varchar"},
+            {SpecificName: "f", RoutineType: "PROCEDURE", OrdinalPosition: 1, DataType: "int"},
+            {SpecificName: "f", RoutineType: "PROCEDURE", OrdinalPosition: 2, DataType: "date"},
+        },
+    }
+
+    c.build()
+
+    if _, ok := c.Table("a"); !ok {
+        t.Error("table a not found")
+    }
+
+    var columns []string
+
+    for _, column := range c.TableColumns("a") {
+        columns = append(columns, column.ColumnName)
+    }
+
+    if !slices.Equal(columns, []string{"id", "b_id"}) {
+        t.Errorf("columns = %v, want [id b_id]", columns)
+    }
+
+    indexes := c.TableIndexes("a")
+
+    if len(indexes) != 2 || indexes[0].Name != "idx" || indexes[0].Statistics[1].ColumnName != "b_id" || indexes[0].Statistics[2].ColumnName != "id" {
+        t.Errorf("indexes = %+v", indexes)
+    }
+
+    // 缺少 REFERENTIAL_CONSTRAINTS 或 KEY_COLUMN_USAGE 的外键被忽略。
+    if foreignKeys := c.TableForeignKeys("a"); len(foreignKeys) != 1 || foreignKeys[0].Referential.ReferencedTableName != "b" {
+        t.Errorf("foreign keys = %+v", foreignKeys)
+    }
+
+    if checks := c.TableChecks("a"); len(checks) != 1 || checks[0].CheckClause != "(`id` > 0)" {
+        t.Errorf("checks = %+v", checks)
+    }
+
+    if len(c.TableColumns("b")) != 1 || len(c.TableIndexes("b")) != 1 || len(c.TableForeignKeys("b")) != 0 {
+        t.Errorf("table b = %v %v", c.TableColumns("b"), c.TableIndexes("b"))
+    }
+
+    if view, ok := c.View("v"); !ok || view.ViewDefinition != "select `a`.`id` AS `id` from `a`" {
+        t.Errorf("view = %+v", view)
+    }
+
+    // 同名的函数与存储过程分别保存，函数的返回值不是参数。
+    if function, ok := c.Routine("FUNCTION", "f"); !ok || len(function.Parameters) != 1 || function.Parameters[0].DataType != "varchar" {
+        t.Errorf("function = %+v", function)
+    }
+
+    if procedure, ok := c.Routine("PROCEDURE", "f"); !ok || len(procedure.Parameters) != 2 {
+        t.Errorf("procedure = %+v", procedure)
+    }
+}
@@ -0,0 +1,263 @@
+package mysqldiff
+
+// ChangeType 对象差异类型。
+type ChangeType string
+
+const (
+    ChangeCreate  ChangeType = "CREATE"
+    ChangeAlter   ChangeType = "ALTER"
+    ChangeReplace ChangeType = "REPLACE"
+    ChangeRename  ChangeType = "RENAME"
+    ChangeDrop    ChangeType = "DROP"
+)
+
+// Change 单个数据库对象的差异。
+type Change interface {
+    ObjectName() string
+}
+
+// AlterSpec ALTER TABLE 中的单项差异。
+type AlterSpec interface {
+    Kind() string
+}
+
+// Index 索引，Statistics 以 SEQ_IN_INDEX 为键。
+type Index struct {
+    Name       string
+    Statistics map[int]Statistic
+}
+
+// ForeignKey 外键。
+type ForeignKey struct {
+    Constraint      TableConstraints
+    Referential     ReferentialConstraints
+    KeyColumnUsages []KeyColumnUsage
+}
+
+// DatabaseChange 库差异，Name 为目标库名，From 为目标库，To 为源库。
+//
+// CREATE 时目标库不存在，From 为空，新建后切换到该库；ALTER 时修改字符集、排序规则与默认加密中不同的项。
+// 只读的库中不能修改对象，ReadOnly 不为空时为单独修改 READ ONLY 的 ALTER，取消只读最先执行，设为只读最后执行。
+type DatabaseChange struct {
+    Type     ChangeType
+    Name     string
+    From     *Schema
+    To       *Schema
+    ReadOnly *bool
+}
+
+func (c *DatabaseChange) ObjectName() string {
+    return c.Name
+}
+
+// Check CHECK 约束。
+type Check struct {
+    Constraint  TableConstraints
+    CheckClause string
+}
+
+// TableChange 表差异。
+//
+// CREATE 时 Table、Columns、Indexes、ForeignKeys、Checks、Partitions 为源表定义；
+// ALTER 时 Table 为源表，Specs 为各项差异；
+// RENAME 时 Table 为源表，OldName 为目标表名，Specs 为重命名后的各项差异；
+// DROP 时 Table 为目标表。
+type TableChange struct {
+    Type        ChangeType
+    Table       Table
+    OldName     string
+    Columns     []Column
+    Indexes     []Index
+    ForeignKeys []ForeignKey
+    Checks      []Check
+    Partitions  []Partition
+    Specs       []AlterSpec
+}
+
+func (c *TableChange) ObjectName() string {
+    return c.Table.TableName
+}
+
+// ViewChange 视图差异，From 为目标视图，To 为源视图。
+type ViewChange struct {
+    Type ChangeType
+    Name string
+    From *View
+    To   *View
+}
+
+func (c *ViewChange) ObjectName() string {
+    return c.Name
+}
+
+// TriggerChange 触发器差异，From 为目标触发器，To 为源触发器。
+//
+// 触发器不能修改，REPLACE 时删除后重建；Follows、Precedes 用于保持同一表、时机、事件的触发器顺序。
+type TriggerChange struct {
+    Type     ChangeType
+    Name     string
+    From     *Trigger
+    To       *Trigger
+    Follows  string
+    Precedes string
+}
+
+func (c *TriggerChange) ObjectName() string {
+    return c.Name
+}
+
+// RoutineChange 存储过程或函数差异，From 为目标对象，To 为源对象。
+//
+// 修改参数、返回值或语句体需要删除后重建，REPLACE 时删除后重建。
+type RoutineChange struct {
+    Type        ChangeType
+    RoutineType string // PROCEDURE 或 FUNCTION
+    Name        string
+    From        *StoredRoutine
+    To          *StoredRoutine
+}
+
+func (c *RoutineChange) ObjectName() string {
+    return c.Name
+}
+
+// EventChange 事件差异，From 为目标事件，To 为源事件。
+type EventChange struct {
+    Type ChangeType
+    Name string
+    From *Event
+    To   *Event
+}
+
+func (c *EventChange) ObjectName() string {
+    return c.Name
+}
+
+// ColumnAdded 新增列，After 为空时表示 FIRST。
+type ColumnAdded struct {
+    Column Column
+    After  string
+}
+
+// ColumnDropped 删除列。
+type ColumnDropped struct {
+    Column Column
+}
+
+// ColumnModified 修改列，From 为目标列，To 为源列，VisibilityOnly 为只有可见性不同。
+type ColumnModified struct {
+    From           Column
+    To             Column
+    After          string
+    VisibilityOnly bool
+}
+
+// ColumnRenamed 重命名列，From 为目标列，To 为源列。
+type ColumnRenamed struct {
+    From  Column
+    To    Column
+    After string
+}
+
+// IndexAdded 新增索引。
+type IndexAdded struct {
+    Index Index
+}
+
+// IndexDropped 删除索引。
+type IndexDropped struct {
+    Index Index
+}
+
+// IndexModified 修改索引（删除后重建），VisibilityOnly 为只有可见性不同，不需要重建。
+type IndexModified struct {
+    From           Index
+    To             Index
+    VisibilityOnly bool
+}
+
+// ForeignKeyAdded 新增外键。
+type ForeignKeyAdded struct {
+    ForeignKey ForeignKey
+}
+
+// ForeignKeyDropped 删除外键。
+type ForeignKeyDropped struct {
+    ForeignKey ForeignKey
+}
+
+// ForeignKeyModified 修改外键（删除后重建）。
+type ForeignKeyModified struct {
+    From ForeignKey
+    To   ForeignKey
+}
+
+// CheckAdded 新增 CHECK 约束。
+type CheckAdded struct {
+    Check Check
+}
+
+// CheckDropped 删除 CHECK 约束。
+type CheckDropped struct {
+    Check Check
+}
+
+// CheckModified 修改 CHECK 约束，只有是否强制检查不同时用 ALTER CHECK 修改，否则删除后重建。
+type CheckModified struct {
+    From Check
+    To   Check
+}
+
+// TableOptionChanged 表选项变更，Name 为 ENGINE、COLLATE、COMMENT、AUTO_INCREMENT 或扩展表选项（见 TableOptions），扩展表选项的值为空时表示默认值。
+type TableOptionChanged struct {
+    Name string
+    From string
+    To   string
+}
+
+// PartitioningChanged 修改分区方式（PARTITION BY），From 为目标表的分区，未分区时为空。
+type PartitioningChanged struct {
+    From []Partition
+    To   []Partition
+}
+
+// PartitioningRemoved 取消分区（REMOVE PARTITIONING）。
+type PartitioningRemoved struct {
+    From []Partition
+}
+
+// PartitionAdded 在末尾新增 RANGE 分区或新增 LIST 分区。
+type PartitionAdded struct {
+    Partitions []Partition
+}
+
+// PartitionDropped 删除 RANGE 分区最前面或超出源表上限的分区，或删除包含源表没有的值的 LIST 分区，分区中的数据一并删除。
+type PartitionDropped struct {
+    Partitions []Partition
+}
+
+// PartitionReorganized 重组分区，From 为目标表中被重组的分区，To 为重组后的分区。
+type PartitionReorganized struct {
+    From []Partition
+    To   []Partition
+}
+
+func (ColumnAdded) Kind() string          { return "COLUMN_ADDED" }
+func (ColumnDropped) Kind() string        { return "COLUMN_DROPPED" }
+func (ColumnModified) Kind() string       { return "COLUMN_MODIFIED" }
+func (ColumnRenamed) Kind() string        { return "COLUMN_RENAMED" }
+func (IndexAdded) Kind() string           { return "INDEX_ADDED" }
+func (IndexDropped) Kind() string         { return "INDEX_DROPPED" }
+func (IndexModified) Kind() string        { return "INDEX_MODIFIED" }
+func (ForeignKeyAdded) Kind() string      { return "FOREIGN_KEY_ADDED" }
+func (ForeignKeyDropped) Kind() string    { return "FOREIGN_KEY_DROPPED" }
+func (ForeignKeyModified) Kind() string   { return "FOREIGN_KEY_MODIFIED" }
+func (CheckAdded) Kind() string           { return "CHECK_ADDED" }
+func (CheckDropped) Kind() string         { return "CHECK_DROPPED" }
+func (CheckModified) Kind() string        { return "CHECK_MODIFIED" }
+func (TableOptionChanged) Kind() string   { return "TABLE_OPTION_CHANGED" }
+func (PartitioningChanged) Kind() string  { return "PARTITIONING_CHANGED" }
+func (PartitioningRemoved) Kind() string  { return "PARTITIONING_REMOVED" }
+func (PartitionAdded) Kind() string       { return "PARTITION_ADDED" }
+func (PartitionDropped) Kind() string     { return "PARTITION_DROPPED" }
+func (PartitionReorganized) Kind() string { return "PARTITION_REORGANIZED" }
@@ -0,0 +1,508 @@
+package mysqldiff
+
+import (
+    "cmp"
+    "database/sql"
+    "strconv"
+    "strings"
+
+    "github.com/samber/lo"
+)
+
+const (
+    StatusAdd    = 1
+    StatusModify = 2
+    StatusDrop   = 3
+)
+
+func resetCalcPosition(columnName string, sourcePos int, targetColumns map[string]Column, status int) {
+    switch status {
+    case StatusAdd:
+        // ADD ...
+        for targetColumnName, targetColumn := range targetColumns {
+            if targetColumn.OrdinalPosition >= sourcePos {
+                targetColumn.OrdinalPosition += 1
+
+                targetColumns[targetColumnName] = targetColumn
+            }
+        }
+        break
+    case StatusModify:
+        // MODIFY ...
+        if _, ok := targetColumns[columnName]; ok {
+            targetColumn := targetColumns[columnName]
+
+            targetColumn.OrdinalPosition = sourcePos
+
+            targetColumns[columnName] = targetColumn
+        }
+        break
+    case StatusDrop:
+        // DROP ...
+        for targetColumnName, targetColumn := range targetColumns {
+            if targetColumn.OrdinalPosition >= sourcePos {
+                targetColumn.OrdinalPosition -= 1
+
+                targetColumns[targetColumnName] = targetColumn
+            }
+        }
+        break
+    }
+}
+
+func compareColumns(sourceColumnsPos map[int]Column, targetColumnsPos map[int]Column, comment bool) bool {
+    if len(sourceColumnsPos) != len(targetColumnsPos) {
+        return false
+    } else {
+        for sourcePos, sourceColumn := range sourceColumnsPos {
+            if _, ok := targetColumnsPos[sourcePos]; ok {
+                targetColumn := targetColumnsPos[sourcePos]
+
+                if !compareColumn(sourceColumn, targetColumn, comment) {
+                    return false
+                }
+            } else {
+                return false
+            }
+
+        }
+    }
+
+    return true
+}
+
+func compareColumn(sourceColumn Column, targetColumn Column, comment bool) bool {
+    if isInvisibleColumn(sourceColumn) != isInvisibleColumn(targetColumn) {
+        return false
+    }
+
+    return compareColumnDefinition(sourceColumn, targetColumn, comment)
+}
+
+// compareColumnDefinition 比对可见性以外的列定义。
+func compareColumnDefinition(sourceColumn Column, targetColumn Column, comment bool) bool {
+    if sourceColumn.ColumnName != targetColumn.ColumnName {
+        return false
+    }
+
+    if sourceColumn.OrdinalPosition != targetColumn.OrdinalPosition {
+        return false
+    }
+
+    if sourceColumn.ColumnDefault.Valid != targetColumn.ColumnDefault.Valid {
+        return false
+    }
+
+    if getNormalizedDefault(sourceColumn) != getNormalizedDefault(targetColumn) {
+        return false
+    }
+
+    if sourceColumn.IsNullable != targetColumn.IsNullable {
+        return false
+    }
+
+    if sourceColumn.DataType != targetColumn.DataType {
+        return false
+    }
+
+    if sourceColumn.CharacterMaximumLength != targetColumn.CharacterMaximumLength {
+        return false
+    }
+
+    // 禁用实际精度检验，因为 TiDB 和 MySQL 在设置不标准的情况下，值会不一样。
+    // if sourceColumn.NumericPrecision != targetColumn.NumericPrecision {
+    //	return false
+    // }
+
+    if sourceColumn.NumericScale != targetColumn.NumericScale {
+        return false
+    }
+
+    if sourceColumn.DatetimePrecision != targetColumn.DatetimePrecision {
+        return false
+    }
+
+    if sourceColumn.CharacterSetName != targetColumn.CharacterSetName {
+        return false
+    }
+
+    if sourceColumn.CollationName != targetColumn.CollationName {
+        return false
+    }
+
+    if sourceColumn.ColumnType != targetColumn.ColumnType {
+        return false
+    }
+
+    if sourceColumn.SrsId != targetColumn.SrsId {
+        return false
+    }
+
+    // MySQL 5.7 没有 DEFAULT_GENERATED。
+    if getColumnExtra(sourceColumn) != getColumnExtra(targetColumn) {
+        return false
+    }
+
+    if getGenerationType(sourceColumn) != getGenerationType(targetColumn) {
+        return false
+    }
+
+    if getNormalizedSchemaExpression(sourceColumn.GenerationExpression) != getNormalizedSchemaExpression(targetColumn.GenerationExpression) {
+        return false
+    }
+
+    if comment {
+        if sourceColumn.ColumnComment != targetColumn.ColumnComment {
+            return false
+        }
+    }
+
+    return true
+}
+
+func compareStatistics(sourceStatisticsMap map[string]map[int]Statistic, targetStatisticsMap map[string]map[int]Statistic) bool {
+    if len(sourceStatisticsMap) != len(targetStatisticsMap) {
+        return false
+    } else {
+        for indexName, sourceStatisticMap := range sourceStatisticsMap {
+            if _, ok := targetStatisticsMap[indexName]; ok {
+                if len(sourceStatisticMap) != len(targetStatisticsMap[indexName]) {
+                    return false
+                } else {
+                    for seqInIndex, sourceStatistic := range sourceStatisticMap {
+                        if _, ok := targetStatisticsMap[indexName][seqInIndex]; ok {
+                            targetStatistic := targetStatisticsMap[indexName][seqInIndex]
+
+                            if !compareStatistic(sourceStatistic, targetStatistic) {
+                                return false
+                            }
+                        } else {
+                            return false
+                        }
+                    }
+                }
+            } else {
+                return false
+            }
+        }
+    }
+
+    return true
+}
+
+func compareStatisticsIndex(sourceStatisticMap map[int]Statistic, targetStatisticMap map[int]Statistic) bool {
+    if len(sourceStatisticMap) != len(targetStatisticMap) {
+        return false
+    } else {
+        for seqInIndex, sourceStatistic := range sourceStatisticMap {
+            if _, ok := targetStatisticMap[seqInIndex]; ok {
+                targetStatistic := targetStatisticMap[seqInIndex]
+
+                if !compareStatistic(sourceStatistic, targetStatistic) {
+                    return false
+                }
+            } else {
+                return false
+            }
+        }
+    }
+
+    return true
+}
+
+func compareStatistic(sourceStatistic Statistic, targetStatistic Statistic) bool {
+    if sourceStatistic.NonUnique != targetStatistic.NonUnique {
+        return false
+    }
+
+    if sourceStatistic.IndexName != targetStatistic.IndexName {
+        return false
+    }
+
+    if sourceStatistic.SeqInIndex != targetStatistic.SeqInIndex {
+        return false
+    }
+
+    if sourceStatistic.ColumnName != targetStatistic.ColumnName {
+        return false
+    }
+
+    if sourceStatistic.EXPRESSION.Valid != targetStatistic.EXPRESSION.Valid {
+        return false
+    }
+
+    if getNormalizedSchemaExpression(sourceStatistic.EXPRESSION.String) != getNormalizedSchemaExpression(targetStatistic.EXPRESSION.String) {
+        return false
+    }
+
+    if isDescending(sourceStatistic) != isDescending(targetStatistic) {
+        return false
+    }
+
+    // 空间索引的 SUB_PART 由存储引擎决定。
+    if sourceStatistic.SubPart != targetStatistic.SubPart && sourceStatistic.IndexType != "SPATIAL" {
+        return false
+    }
+
+    if sourceStatistic.IndexType != targetStatistic.IndexType {
+        return false
+    }
+
+    if sourceStatistic.Parser != targetStatistic.Parser {
+        return false
+    }
+
+    if isVisibleIndex(sourceStatistic) != isVisibleIndex(targetStatistic) {
+        return false
+    }
+
+    return true
+}
+
+// isIndexVisibilityChanged 两个索引是否只有可见性不同。
+func isIndexVisibilityChanged(sourceStatisticMap map[int]Statistic, targetStatisticMap map[int]Statistic) bool {
+    if len(sourceStatisticMap) <= 0 || len(targetStatisticMap) <= 0 {
+        return false
+    }
+
+    visible := isVisibleIndex(lo.Values(sourceStatisticMap)[0])
+
+    if visible == isVisibleIndex(lo.Values(targetStatisticMap)[0]) {
+        return false
+    }
+
+    statisticMap := make(map[int]Statistic)
+
+    for seqInIndex, targetStatistic := range targetStatisticMap {
+        targetStatistic.IsVisible = sql.NullString{String: lo.Ternary(visible, "YES", "NO"), Valid: true}
+        statisticMap[seqInIndex] = targetStatistic
+    }
+
+    return compareStatisticsIndex(sourceStatisticMap, statisticMap)
+}
+
+// compareCheck 比对 CHECK 约束的表达式与是否强制检查。
+func compareCheck(sourceCheck Check, targetCheck Check) bool {
+    if getNormalizedSchemaExpression(sourceCheck.CheckClause) != getNormalizedSchemaExpression(targetCheck.CheckClause) {
+        return false
+    }
+
+    return isEnforced(sourceCheck) == isEnforced(targetCheck)
+}
+
+func compareForeignKey(sourceForeignKey ForeignKey, targetForeignKey ForeignKey) bool {
+    sourceTableConstraint := sourceForeignKey.Constraint
+    targetTableConstraint := targetForeignKey.Constraint
+
+    if sourceTableConstraint.ConstraintName != targetTableConstraint.ConstraintName {
+        return false
+    }
+
+    if sourceTableConstraint.TableName != targetTableConstraint.TableName {
+        return false
+    }
+
+    if sourceTableConstraint.ConstraintType != targetTableConstraint.ConstraintType {
+        return false
+    }
+
+    sourceReferentialConstraint := sourceForeignKey.Referential
+    targetReferentialConstraint := targetForeignKey.Referential
+
+    if sourceReferentialConstraint.UniqueConstraintName != targetReferentialConstraint.UniqueConstraintName {
+        return false
+    }
+
+    if sourceReferentialConstraint.MatchOption != targetReferentialConstraint.MatchOption {
+        return false
+    }
+
+    if sourceReferentialConstraint.UpdateRule != targetReferentialConstraint.UpdateRule {
+        return false
+    }
+
+    if sourceReferentialConstraint.DeleteRule != targetReferentialConstraint.DeleteRule {
+        return false
+    }
+
+    if sourceReferentialConstraint.TableName != targetReferentialConstraint.TableName {
+        return false
+    }
+
+    if sourceReferentialConstraint.ReferencedTableName != targetReferentialConstraint.ReferencedTableName {
+        return false
+    }
+
+    var (
+        s1, s2 []string
+        t1, t2 []string
+    )
+
+    for _, sourceKeyColumnUsage := range sourceForeignKey.KeyColumnUsages {
+        s1 = append(s1, sourceKeyColumnUsage.ColumnName)
+        s2 = append(s2, sourceKeyColumnUsage.ReferencedColumnName)
+    }
+
+    for _, targetKeyColumnUsage := range targetForeignKey.KeyColumnUsages {
+        t1 = append(t1, targetKeyColumnUsage.ColumnName)
+        t2 = append(t2, targetKeyColumnUsage.ReferencedColumnName)
+    }
+
+    if strings.Join(s1, ",") != strings.Join(t1, ",") || strings.Join(s2, ",") != strings.Join(t2, ",") {
+        return false
+    }
+
+    return true
+}
+
+// compareTrigger 比对触发器，任一方未指定定义者时不比对定义者，顺序见 differ.unchangedTriggers。
+func compareTrigger(sourceTrigger Trigger, targetTrigger Trigger) bool {
+    if sourceTrigger.EventObjectTable != targetTrigger.EventObjectTable {
+        return false
+    }
+
+    if sourceTrigger.ActionTiming != targetTrigger.ActionTiming {
+        return false
+    }
+
+    if sourceTrigger.EventManipulation != targetTrigger.EventManipulation {
+        return false
+    }
+
+    if strings.TrimSpace(sourceTrigger.ActionStatement) != strings.TrimSpace(targetTrigger.ActionStatement) {
+        return false
+    }
+
+    if sourceTrigger.DEFINER != "" && targetTrigger.DEFINER != "" && sourceTrigger.DEFINER != targetTrigger.DEFINER {
+        return false
+    }
+
+    return true
+}
+
+// compareRoutine 比对存储过程或函数的定义（参数、返回值、语句体、DETERMINISTIC），任一方未指定定义者时不比对定义者。
+func compareRoutine(sourceRoutine StoredRoutine, targetRoutine StoredRoutine) bool {
+    if getRoutineParameters(sourceRoutine.Parameters) != getRoutineParameters(targetRoutine.Parameters) {
+        return false
+    }
+
+    if sourceRoutine.Routine.DtdIdentifier.String != targetRoutine.Routine.DtdIdentifier.String {
+        return false
+    }
+
+    if strings.TrimSpace(sourceRoutine.Routine.RoutineDefinition.String) != strings.TrimSpace(targetRoutine.Routine.RoutineDefinition.String) {
+        return false
+    }
+
+    if sourceRoutine.Routine.IsDeterministic != targetRoutine.Routine.IsDeterministic {
+        return false
+    }
+
+    if sourceRoutine.Routine.DEFINER != "" && targetRoutine.Routine.DEFINER != "" && sourceRoutine.Routine.DEFINER != targetRoutine.Routine.DEFINER {
+        return false
+    }
+
+    return true
+}
+
+// compareRoutineCharacteristics 比对可以用 ALTER PROCEDURE/FUNCTION 修改的特性。
+func compareRoutineCharacteristics(sourceRoutine Routine, targetRoutine Routine, comment bool) bool {
+    if sourceRoutine.SqlDataAccess != targetRoutine.SqlDataAccess {
+        return false
+    }
+
+    if sourceRoutine.SecurityType != targetRoutine.SecurityType {
+        return false
+    }
+
+    if comment && sourceRoutine.RoutineComment != targetRoutine.RoutineComment {
+        return false
+    }
+
+    return true
+}
+
+// compareSchema 比对库的排序规则与默认加密，任一方没有 DEFAULT_ENCRYPTION 时不比对该项。
+func compareSchema(sourceSchema Schema, targetSchema Schema) bool {
+    if sourceSchema.DefaultCollationName != targetSchema.DefaultCollationName {
+        return false
+    }
+
+    if sourceSchema.DefaultEncryption.Valid && targetSchema.DefaultEncryption.Valid {
+        return sourceSchema.DefaultEncryption.String == targetSchema.DefaultEncryption.String
+    }
+
+    return true
+}
+
+// compareEvent 比对事件，任一方未指定 STARTS、ENDS 或定义者时不比对该项。
+func compareEvent(sourceEvent Event, targetEvent Event, comment bool) bool {
+    if getEventSchedule(sourceEvent, targetEvent.STARTS.Valid, targetEvent.ENDS.Valid) != getEventSchedule(targetEvent, sourceEvent.STARTS.Valid, sourceEvent.ENDS.Valid) {
+        return false
+    }
+
+    if sourceEvent.STATUS != targetEvent.STATUS {
+        return false
+    }
+
+    if sourceEvent.OnCompletion != targetEvent.OnCompletion {
+        return false
+    }
+
+    if strings.TrimSpace(sourceEvent.EventDefinition) != strings.TrimSpace(targetEvent.EventDefinition) {
+        return false
+    }
+
+    if comment && sourceEvent.EventComment != targetEvent.EventComment {
+        return false
+    }
+
+    if sourceEvent.DEFINER != "" && targetEvent.DEFINER != "" && sourceEvent.DEFINER != targetEvent.DEFINER {
+        return false
+    }
+
+    return true
+}
+
+// comparePartition 比对分区名与分区值。
+func comparePartition(sourcePartition Partition, targetPartition Partition) bool {
+    if sourcePartition.PartitionName.String != targetPartition.PartitionName.String {
+        return false
+    }
+
+    return strings.ReplaceAll(sourcePartition.PartitionDescription.String, ", ", ",") == strings.ReplaceAll(targetPartition.PartitionDescription.String, ", ", ",")
+}
+
+// comparePartitionBound 比对两个 RANGE 分区的上限，小于、等于、大于时分别返回 -1、0、1。
+//
+// RANGE COLUMNS 逐列比对，MAXVALUE 最大，整数按数值比对，其他值（如日期字符串）按字符串比对。
+func comparePartitionBound(a string, b string) int {
+    aValues, bValues := getPartitionValues(a), getPartitionValues(b)
+
+    for i := 0; i < len(aValues) && i < len(bValues); i++ {
+        if result := comparePartitionValue(aValues[i], bValues[i]); result != 0 {
+            return result
+        }
+    }
+
+    return cmp.Compare(len(aValues), len(bValues))
+}
+
+func comparePartitionValue(a string, b string) int {
+    if a == b {
+        return 0
+    }
+
+    if a == "MAXVALUE" || b == "MAXVALUE" {
+        return lo.Ternary(a == "MAXVALUE", 1, -1)
+    }
+
+    aInt, aErr := strconv.ParseInt(a, 10, 64)
+    bInt, bErr := strconv.ParseInt(b, 10, 64)
+
+    if aErr == nil && bErr == nil {
+        return cmp.Compare(aInt, bInt)
+    }
+
+    return cmp.Compare(strings.Trim(a, "'"), strings.Trim(b, "'"))
+}
@@ -0,0 +1,1190 @@
+package mysqldiff
+
+import (
+    "database/sql"
+    "slices"
+    "strings"
+    "testing"
+)
+
+func TestCompare(t *testing.T) {
+    tests := []struct {
+        name   string
+        source string
+        target string
+        script []string
+    }{
+        {
+            name:   "无差异",
+            source: "CREATE TABLE a (id int NOT NULL, PRIMARY KEY (id));",
+            target: "CREATE TABLE a (`id` int NOT NULL, PRIMARY KEY (`id`));",
+        },
+        {
+            name:   "新建表",
+            source: "CREATE TABLE a (id int); CREATE TABLE b (id int NOT NULL, PRIMARY KEY (id));",
+            target: "CREATE TABLE a (id int);",
+            script: []string{"CREATE TABLE IF NOT EXISTS `b` (\n  `id` int NOT NULL,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB"},
+        },
+        {
+            name:   "删除表",
+            source: "CREATE TABLE a (id int);",
+            target: "CREATE TABLE a (id int); CREATE TABLE b (id int);",
+            script: []string{"DROP TABLE IF EXISTS `b`;"},
+        },
+        {
+            name:   "新增与删除列",
+            source: "CREATE TABLE a (id int NOT NULL, n varchar(10));",
+            target: "CREATE TABLE a (id int NOT NULL, m int);",
+            script: []string{"ALTER TABLE `a`\n  DROP COLUMN `m`,\n  ADD COLUMN `n` varchar(10) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL AFTER `id`;"},
+        },
+        {
+            name:   "修改列",
+            source: "CREATE TABLE a (id int NOT NULL, n varchar(20) NOT NULL);",
+            target: "CREATE TABLE a (id int NOT NULL, n varchar(10));",
+            script: []string{"ALTER TABLE `a`\n  MODIFY COLUMN `n` varchar(20) NOT NULL AFTER `id`;"},
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            result := Compare(mustParseDDL(t, tt.source), mustParseDDL(t, tt.target), Options{})
+
+            if len(tt.script) <= 0 {
+                if script := result.Script(); script != "" {
+                    t.Fatalf("script = %q, want empty", script)
+                }
+
+                return
+            }
+
+            assertScript(t, result, tt.script...)
+            assertScript(t, result, "SET NAMES utf8mb4;", "SET FOREIGN_KEY_CHECKS=0;", "SET FOREIGN_KEY_CHECKS=1;")
+        })
+    }
+}
+
+func TestCompareChanges(t *testing.T) {
+    source := mustParseDDL(t, `CREATE DATABASE db DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_bin;
+CREATE TABLE a (id int, n int);
+CREATE TABLE c (id int);
+CREATE VIEW v AS SELECT id FROM a;
+CREATE TRIGGER tr AFTER INSERT ON a FOR EACH ROW SET @x = NEW.id;
+CREATE PROCEDURE p() SELECT 1;
+CREATE EVENT e ON SCHEDULE EVERY 1 DAY DO DELETE FROM c;`)
+    target := mustParseDDL(t, `CREATE DATABASE db DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+CREATE TABLE a (id int) COLLATE utf8mb4_bin;
+CREATE TABLE b (id int, name varchar(10));
+CREATE TRIGGER old BEFORE DELETE ON a FOR EACH ROW SET @x = 1;`)
+
+    result := Compare(source, target, Options{Database: true})
+
+    // 按执行顺序：库、存储过程、表与视图、删除触发器、新建触发器、事件，同类按对象名排序。
+    want := []string{
+        "DatabaseChange ALTER db",
+        "RoutineChange CREATE p",
+        "TableChange ALTER a",
+        "TableChange DROP b",
+        "TableChange CREATE c",
+        "ViewChange CREATE v",
+        "TriggerChange DROP old",
+        "TriggerChange CREATE tr",
+        "EventChange CREATE e",
+    }
+
+    var got []string
+
+    for _, change := range result.Changes {
+        var name string
+
+        switch c := change.(type) {
+        case *DatabaseChange:
+            name = "DatabaseChange " + string(c.Type)
+        case *TableChange:
+            name = "TableChange " + string(c.Type)
+        case *ViewChange:
+            name = "ViewChange " + string(c.Type)
+        case *TriggerChange:
+            name = "TriggerChange " + string(c.Type)
+        case *RoutineChange:
+            name = "RoutineChange " + string(c.Type)
+        case *EventChange:
+            name = "EventChange " + string(c.Type)
+        }
+
+        got = append(got, name+" "+change.ObjectName())
+    }
+
+    if !slices.Equal(got, want) {
+        t.Fatalf("changes = %q, want %q", got, want)
+    }
+
+    if kinds := getSpecKinds(result); !slices.Equal(kinds, []string{"COLUMN_ADDED"}) {
+        t.Fatalf("specs = %v, want [COLUMN_ADDED]", kinds)
+    }
+
+    // 语句按差异顺序渲染，首尾为 SET NAMES 与 SET FOREIGN_KEY_CHECKS。
+    statements := result.Statements()
+
+    if statements[0] != "SET NAMES utf8mb4;" || statements[1] != "SET FOREIGN_KEY_CHECKS=0;" || statements[len(statements)-1] != "SET FOREIGN_KEY_CHECKS=1;" {
+        t.Fatalf("statements = %q", statements)
+    }
+
+    renderer := result.Renderer()
+    index := 2
+
+    for _, change := range result.Changes {
+        for _, statement := range renderer.Render(change) {
+            if statements[index] != statement {
+                t.Fatalf("statements[%d] = %q, want %q", index, statements[index], statement)
+            }
+
+            index++
+        }
+    }
+}
+
+func TestResultDown(t *testing.T) {
+    tests := []struct {
+        name    string
+        source  string
+        target  string
+        options Options
+        down    []string
+    }{
+        {
+            name:   "删除新建的表",
+            source: "CREATE TABLE a (id int); CREATE TABLE b (id int);",
+            target: "CREATE TABLE a (id int);",
+            down:   []string{"DROP TABLE IF EXISTS `b`;"},
+        },
+        {
+            name:   "恢复删除的列",
+            source: "CREATE TABLE a (id int);",
+            target: "CREATE TABLE a (id int, n varchar(10) NOT NULL DEFAULT 'x');",
+            down:   []string{"ADD COLUMN `n` varchar(10) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT 'x' AFTER `id`;"},
+        },
+        {
+            name:    "按提示重命名的列改回原名",
+            source:  "CREATE TABLE users (id int, full_name varchar(20), age int);",
+            target:  "CREATE TABLE user (id int, name varchar(10), age int);",
+            options: Options{Rename: true, Hints: Hints{Tables: map[string]string{"user": "users"}, Columns: map[string]map[string]string{"users": {"name": "full_name"}}}},
+            down:    []string{"RENAME TABLE `users` TO `user`;", "CHANGE COLUMN `full_name` `name` varchar(10)"},
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            source, target := mustParseDDL(t, tt.source), mustParseDDL(t, tt.target)
+
+            result := Compare(source, target, tt.options)
+            down := result.Down()
+
+            assertScript(t, down, tt.down...)
+
+            // 回滚的回滚与原差异一致。
+            if script := down.Down().Script(); script != result.Script() {
+                t.Fatalf("down of down:\n%s\nwant:\n%s", script, result.Script())
+            }
+        })
+    }
+}
+
+func TestResultDownWithoutCatalogs(t *testing.T) {
+    result := &Result{Schema: Schema{SchemaName: "db"}, Changes: []Change{&TableChange{Type: ChangeDrop, Table: Table{TableName: "t"}}}}
+
+    if down := result.Down(); len(down.Changes) > 0 || down.Schema.SchemaName != "db" {
+        t.Fatalf("down = %+v, want no changes", down)
+    }
+}
+
+func TestCompareRenameColumn(t *testing.T) {
+    hints := Hints{Columns: map[string]map[string]string{"t": {"name": "full_name"}}}
+
+    tests := []struct {
+        name    string
+        source  string
+        target  string
+        options Options
+        kinds   []string
+        script  []string
+    }{
+        {
+            name:    "同一位置的相同定义，索引随列重命名",
+            source:  "id int, full_name varchar(20) NOT NULL DEFAULT '', KEY idx (full_name)",
+            target:  "id int, name varchar(20) NOT NULL DEFAULT '', KEY idx (name)",
+            options: Options{Rename: true},
+            kinds:   []string{"COLUMN_RENAMED"},
+            script:  []string{"ALTER TABLE `t`\n  CHANGE COLUMN `name` `full_name` varchar(20) NOT NULL DEFAULT '' AFTER `id`;"},
+        },
+        {
+            name:   "未开启检测",
+            source: "id int, full_name varchar(20) NOT NULL",
+            target: "id int, name varchar(20) NOT NULL",
+            kinds:  []string{"COLUMN_DROPPED", "COLUMN_ADDED"},
+            script: []string{"DROP COLUMN `name`", "ADD COLUMN `full_name`"},
+        },
+        {
+            name:    "定义不同",
+            source:  "id int, full_name varchar(30) NOT NULL",
+            target:  "id int, name varchar(20) NOT NULL",
+            options: Options{Rename: true},
+            kinds:   []string{"COLUMN_DROPPED", "COLUMN_ADDED"},
+        },
+        {
+            name:    "位置不同",
+            source:  "full_name varchar(20) NOT NULL, id int",
+            target:  "id int, name varchar(20) NOT NULL",
+            options: Options{Rename: true},
+            kinds:   []string{"COLUMN_DROPPED", "COLUMN_ADDED"},
+        },
+        {
+            name:    "按提示重命名并修改定义",
+            source:  "id int, full_name varchar(30) NOT NULL",
+            target:  "id int, name varchar(20) NOT NULL",
+            options: Options{Hints: hints},
+            kinds:   []string{"COLUMN_RENAMED"},
+            script:  []string{"CHANGE COLUMN `name` `full_name` varchar(30) NOT NULL AFTER `id`;"},
+        },
+        {
+            name:    "重命名后索引不同",
+            source:  "id int, full_name varchar(20) NOT NULL, KEY idx (id, full_name)",
+            target:  "id int, name varchar(20) NOT NULL, KEY idx (name)",
+            options: Options{Rename: true},
+            kinds:   []string{"COLUMN_RENAMED", "INDEX_MODIFIED"},
+            script:  []string{"CHANGE COLUMN `name` `full_name`", "DROP INDEX `idx`,\n  ADD KEY `idx` (`id`,`full_name`);"},
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            source := mustParseDDL(t, "CREATE TABLE t ("+tt.source+");")
+            target := mustParseDDL(t, "CREATE TABLE t ("+tt.target+");")
+
+            result := Compare(source, target, tt.options)
+
+            if kinds := getSpecKinds(result); !slices.Equal(kinds, tt.kinds) {
+                t.Fatalf("specs = %v, want %v\n%s", kinds, tt.kinds, result.Script())
+            }
+
+            assertScript(t, result, tt.script...)
+        })
+    }
+}
+
+// getChangeTypes 返回全部差异的类型与对象名。
+func getChangeTypes(result *Result) []string {
+    var types []string
+
+    for _, change := range result.Changes {
+        if c, ok := change.(*TableChange); ok {
+            types = append(types, string(c.Type)+" "+c.ObjectName())
+        }
+    }
+
+    return types
+}
+
+func TestCompareRenameTable(t *testing.T) {
+    tests := []struct {
+        name    string
+        source  string
+        target  string
+        options Options
+        changes []string
+        script  []string
+    }{
+        {
+            name:    "列与索引相同",
+            source:  "CREATE TABLE users (id int NOT NULL, name varchar(20), PRIMARY KEY (id));",
+            target:  "CREATE TABLE user (id int NOT NULL, name varchar(20), PRIMARY KEY (id));",
+            options: Options{Rename: true},
+            changes: []string{"RENAME users"},
+            script:  []string{"RENAME TABLE `user` TO `users`;"},
+        },
+        {
+            name:    "未开启检测",
+            source:  "CREATE TABLE users (id int);",
+            target:  "CREATE TABLE user (id int);",
+            changes: []string{"DROP user", "CREATE users"},
+        },
+        {
+            name:    "多个候选",
+            source:  "CREATE TABLE users (id int);",
+            target:  "CREATE TABLE user (id int); CREATE TABLE member (id int);",
+            options: Options{Rename: true},
+            changes: []string{"DROP member", "DROP user", "CREATE users"},
+        },
+        {
+            name:    "列不同",
+            source:  "CREATE TABLE users (id int, name varchar(20));",
+            target:  "CREATE TABLE user (id int);",
+            options: Options{Rename: true},
+            changes: []string{"DROP user", "CREATE users"},
+        },
+        {
+            name:    "按提示重命名并修改",
+            source:  "CREATE TABLE users (id int, name varchar(20));",
+            target:  "CREATE TABLE user (id int);",
+            options: Options{Hints: Hints{Tables: map[string]string{"user": "users"}}},
+            changes: []string{"RENAME users"},
+            script:  []string{"RENAME TABLE `user` TO `users`;", "ALTER TABLE `users`\n  ADD COLUMN `name`"},
+        },
+        {
+            name:    "视图不检测",
+            source:  "CREATE TABLE t (id int); CREATE VIEW v2 AS SELECT id FROM t;",
+            target:  "CREATE TABLE t (id int); CREATE VIEW v1 AS SELECT id FROM t;",
+            options: Options{Rename: true},
+            changes: nil,
+            script:  []string{"DROP VIEW IF EXISTS `v1`;", "VIEW `v2` AS SELECT id FROM t;"},
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            result := Compare(mustParseDDL(t, tt.source), mustParseDDL(t, tt.target), tt.options)
+
+            if changes := getChangeTypes(result); !slices.Equal(changes, tt.changes) {
+                t.Fatalf("changes = %q, want %q\n%s", changes, tt.changes, result.Script())
+            }
+
+            assertScript(t, result, tt.script...)
+        })
+    }
+}
+
+func TestCompareTrigger(t *testing.T) {
+    const tables = "CREATE TABLE t (id int); CREATE TABLE l (id int);\n"
+
+    tests := []struct {
+        name    string
+        source  string
+        target  string
+        changes []string
+        script  []string
+    }{
+        {
+            name:   "相同",
+            source: "CREATE TRIGGER a AFTER INSERT ON t FOR EACH ROW INSERT INTO l VALUES (NEW.id);",
+            target: "CREATE DEFINER=`root`@`%` TRIGGER `a` AFTER INSERT ON `t` FOR EACH ROW INSERT INTO l VALUES (NEW.id) ;",
+        },
+        {
+            name:    "新建",
+            source:  "CREATE TRIGGER a AFTER INSERT ON t FOR EACH ROW INSERT INTO l VALUES (NEW.id);",
+            changes: []string{"CREATE a"},
+            script:  []string{"DELIMITER ;;\nCREATE TRIGGER `a` AFTER INSERT ON `t` FOR EACH ROW INSERT INTO l VALUES (NEW.id);;\nDELIMITER ;"},
+        },
+        {
+            name:    "删除",
+            target:  "CREATE TRIGGER a AFTER INSERT ON t FOR EACH ROW INSERT INTO l VALUES (NEW.id);",
+            changes: []string{"DROP a"},
+            script:  []string{"DROP TRIGGER IF EXISTS `a`;"},
+        },
+        {
+            name:    "修改语句体时重建",
+            source:  "CREATE TRIGGER a AFTER INSERT ON t FOR EACH ROW INSERT INTO l VALUES (NEW.id + 1);",
+            target:  "CREATE TRIGGER a AFTER INSERT ON t FOR EACH ROW INSERT INTO l VALUES (NEW.id);",
+            changes: []string{"REPLACE a"},
+            script:  []string{"DROP TRIGGER IF EXISTS `a`;\nDELIMITER ;;\nCREATE TRIGGER `a` AFTER INSERT ON `t` FOR EACH ROW INSERT INTO l VALUES (NEW.id + 1);;"},
+        },
+        {
+            name:    "修改时机时重建",
+            source:  "CREATE TRIGGER a BEFORE INSERT ON t FOR EACH ROW SET @x = 1;",
+            target:  "CREATE TRIGGER a AFTER INSERT ON t FOR EACH ROW SET @x = 1;",
+            changes: []string{"REPLACE a"},
+        },
+        {
+            name:    "排在已有触发器之后",
+            source:  "CREATE TRIGGER a AFTER INSERT ON t FOR EACH ROW SET @x = 1; CREATE TRIGGER b AFTER INSERT ON t FOR EACH ROW FOLLOWS a SET @x = 2;",
+            target:  "CREATE TRIGGER a AFTER INSERT ON t FOR EACH ROW SET @x = 1;",
+            changes: []string{"CREATE b"},
+            script:  []string{"CREATE TRIGGER `b` AFTER INSERT ON `t` FOR EACH ROW FOLLOWS `a` SET @x = 2;;"},
+        },
+        {
+            name:    "排在已有触发器之前",
+            source:  "CREATE TRIGGER b AFTER INSERT ON t FOR EACH ROW SET @x = 2; CREATE TRIGGER a AFTER INSERT ON t FOR EACH ROW PRECEDES b SET @x = 1;",
+            target:  "CREATE TRIGGER b AFTER INSERT ON t FOR EACH ROW SET @x = 2;",
+            changes: []string{"CREATE a"},
+            script:  []string{"CREATE TRIGGER `a` AFTER INSERT ON `t` FOR EACH ROW PRECEDES `b` SET @x = 1;;"},
+        },
+        {
+            name:    "插入到中间",
+            source:  "CREATE TRIGGER a AFTER INSERT ON t FOR EACH ROW SET @x = 1; CREATE TRIGGER b AFTER INSERT ON t FOR EACH ROW FOLLOWS a SET @x = 2; CREATE TRIGGER c AFTER INSERT ON t FOR EACH ROW FOLLOWS b SET @x = 3;",
+            target:  "CREATE TRIGGER a AFTER INSERT ON t FOR EACH ROW SET @x = 1; CREATE TRIGGER c AFTER INSERT ON t FOR EACH ROW FOLLOWS a SET @x = 3;",
+            changes: []string{"CREATE b"},
+            script:  []string{"FOR EACH ROW FOLLOWS `a` SET @x = 2;;"},
+        },
+        {
+            name:    "新建第一个时后一个需要重建",
+            source:  "CREATE TRIGGER x AFTER INSERT ON t FOR EACH ROW SET @x = 0; CREATE TRIGGER y AFTER INSERT ON t FOR EACH ROW FOLLOWS x SET @x = 2; CREATE TRIGGER z AFTER INSERT ON t FOR EACH ROW FOLLOWS y SET @x = 3;",
+            target:  "CREATE TRIGGER y AFTER INSERT ON t FOR EACH ROW SET @x = 1; CREATE TRIGGER z AFTER INSERT ON t FOR EACH ROW FOLLOWS y SET @x = 3;",
+            changes: []string{"CREATE x", "REPLACE y"},
+            script:  []string{"FOR EACH ROW PRECEDES `z` SET @x = 0;;", "FOR EACH ROW FOLLOWS `x` SET @x = 2;;"},
+        },
+        {
+            name:    "交换顺序时重建",
+            source:  "CREATE TRIGGER b AFTER INSERT ON t FOR EACH ROW SET @x = 2; CREATE TRIGGER a AFTER INSERT ON t FOR EACH ROW FOLLOWS b SET @x = 1;",
+            target:  "CREATE TRIGGER a AFTER INSERT ON t FOR EACH ROW SET @x = 1; CREATE TRIGGER b AFTER INSERT ON t FOR EACH ROW FOLLOWS a SET @x = 2;",
+            changes: []string{"REPLACE b", "REPLACE a"},
+            script:  []string{"CREATE TRIGGER `b` AFTER INSERT ON `t` FOR EACH ROW SET @x = 2;;", "FOR EACH ROW FOLLOWS `b` SET @x = 1;;"},
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            result := Compare(mustParseDDL(t, tables+tt.source), mustParseDDL(t, tables+tt.target), Options{})
+
+            if changes := getObjectChanges(result); !slices.Equal(changes, tt.changes) {
+                t.Fatalf("changes = %q, want %q\n%s", changes, tt.changes, result.Script())
+            }
+
+            assertScript(t, result, tt.script...)
+        })
+    }
+}
+
+func TestCompareRoutine(t *testing.T) {
+    tests := []struct {
+        name    string
+        source  string
+        target  string
+        options Options
+        changes []string
+        script  []string
+    }{
+        {
+            name:   "相同",
+            source: "CREATE FUNCTION f(a int) RETURNS int DETERMINISTIC RETURN a + 1;",
+            target: "CREATE DEFINER=`root`@`%` FUNCTION `f`(`a` int) RETURNS int DETERMINISTIC RETURN a + 1;",
+        },
+        {
+            name:    "新建",
+            source:  "CREATE PROCEDURE p(IN a int, OUT b varchar(10)) SELECT a INTO b;",
+            changes: []string{"CREATE PROCEDURE p"},
+            script:  []string{"DELIMITER ;;\nCREATE PROCEDURE `p`(IN `a` int, OUT `b` varchar(10)) NOT DETERMINISTIC CONTAINS SQL SQL SECURITY DEFINER\nSELECT a INTO b;;\nDELIMITER ;"},
+        },
+        {
+            name:    "删除",
+            target:  "CREATE FUNCTION f() RETURNS int RETURN 1;",
+            changes: []string{"DROP FUNCTION f"},
+            script:  []string{"DROP FUNCTION IF EXISTS `f`;"},
+        },
+        {
+            name:    "同名的存储过程与函数",
+            source:  "CREATE FUNCTION f() RETURNS int RETURN 1; CREATE PROCEDURE f() SELECT 1;",
+            target:  "CREATE FUNCTION f() RETURNS int RETURN 1;",
+            changes: []string{"CREATE PROCEDURE f"},
+        },
+        {
+            name:    "修改语句体时重建",
+            source:  "CREATE FUNCTION f(a int) RETURNS int DETERMINISTIC RETURN a + 2;",
+            target:  "CREATE FUNCTION f(a int) RETURNS int DETERMINISTIC RETURN a + 1;",
+            changes: []string{"REPLACE FUNCTION f"},
+            script:  []string{"DROP FUNCTION IF EXISTS `f`;\nDELIMITER ;;\nCREATE FUNCTION `f`(`a` int) RETURNS int DETERMINISTIC CONTAINS SQL SQL SECURITY DEFINER\nRETURN a + 2;;"},
+        },
+        {
+            name:    "修改参数时重建",
+            source:  "CREATE PROCEDURE p(IN a bigint) SELECT a;",
+            target:  "CREATE PROCEDURE p(IN a int) SELECT a;",
+            changes: []string{"REPLACE PROCEDURE p"},
+        },
+        {
+            name:    "修改返回值时重建",
+            source:  "CREATE FUNCTION f() RETURNS bigint RETURN 1;",
+            target:  "CREATE FUNCTION f() RETURNS int RETURN 1;",
+            changes: []string{"REPLACE FUNCTION f"},
+        },
+        {
+            name:    "修改特性",
+            source:  "CREATE PROCEDURE p() SQL SECURITY INVOKER READS SQL DATA SELECT 1;",
+            target:  "CREATE PROCEDURE p() SELECT 1;",
+            changes: []string{"ALTER PROCEDURE p"},
+            script:  []string{"ALTER PROCEDURE `p` READS SQL DATA SQL SECURITY INVOKER;"},
+        },
+        {
+            name:   "不比对注释",
+            source: "CREATE PROCEDURE p() COMMENT 'x' SELECT 1;",
+            target: "CREATE PROCEDURE p() SELECT 1;",
+        },
+        {
+            name:    "比对注释",
+            source:  "CREATE PROCEDURE p() COMMENT 'x' SELECT 1;",
+            target:  "CREATE PROCEDURE p() SELECT 1;",
+            options: Options{Comment: true},
+            changes: []string{"ALTER PROCEDURE p"},
+            script:  []string{"ALTER PROCEDURE `p` COMMENT 'x' CONTAINS SQL SQL SECURITY DEFINER;"},
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            result := Compare(mustParseDDL(t, tt.source), mustParseDDL(t, tt.target), tt.options)
+
+            if changes := getObjectChanges(result); !slices.Equal(changes, tt.changes) {
+                t.Fatalf("changes = %q, want %q\n%s", changes, tt.changes, result.Script())
+            }
+
+            assertScript(t, result, tt.script...)
+        })
+    }
+}
+
+func TestCompareEvent(t *testing.T) {
+    const every = "CREATE EVENT e ON SCHEDULE EVERY 1 DAY STARTS '2024-01-01 00:00:00' DO DELETE FROM l;"
+
+    tests := []struct {
+        name    string
+        source  string
+        target  string
+        options Options
+        changes []string
+        script  []string
+    }{
+        {
+            name:   "相同",
+            source: every,
+            target: "CREATE DEFINER=`root`@`%` EVENT `e` ON SCHEDULE EVERY 1 DAY STARTS '2024-01-01 00:00:00' ON COMPLETION NOT PRESERVE ENABLE DO DELETE FROM l;",
+        },
+        {
+            name:   "一侧没有 STARTS",
+            source: every,
+            target: "CREATE EVENT e ON SCHEDULE EVERY 1 DAY DO DELETE FROM l;",
+        },
+        {
+            name:    "新建",
+            source:  "CREATE EVENT n ON SCHEDULE AT '2030-01-01 00:00:00' ON COMPLETION PRESERVE DISABLE COMMENT 'x' DO DELETE FROM l;",
+            changes: []string{"CREATE n"},
+            script:  []string{"DELIMITER ;;\nCREATE EVENT `n` ON SCHEDULE AT '2030-01-01 00:00:00' ON COMPLETION PRESERVE DISABLE DO DELETE FROM l;;\nDELIMITER ;"},
+        },
+        {
+            name:    "删除",
+            target:  every,
+            changes: []string{"DROP e"},
+            script:  []string{"DROP EVENT IF EXISTS `e`;"},
+        },
+        {
+            name:    "修改计划",
+            source:  strings.Replace(every, "EVERY 1 DAY", "EVERY 2 HOUR", 1),
+            target:  every,
+            changes: []string{"ALTER e"},
+            script:  []string{"ALTER EVENT `e` ON SCHEDULE EVERY 2 HOUR STARTS '2024-01-01 00:00:00' ON COMPLETION NOT PRESERVE ENABLE DO DELETE FROM l;;"},
+        },
+        {
+            name:    "修改状态",
+            source:  strings.Replace(every, " DO ", " DISABLE DO ", 1),
+            target:  every,
+            changes: []string{"ALTER e"},
+            script:  []string{"ON COMPLETION NOT PRESERVE DISABLE DO DELETE FROM l;;"},
+        },
+        {
+            name:    "修改语句体",
+            source:  strings.Replace(every, "DELETE FROM l", "DELETE FROM l WHERE id > 0", 1),
+            target:  every,
+            changes: []string{"ALTER e"},
+        },
+        {
+            name:   "不比对注释",
+            source: strings.Replace(every, " DO ", " COMMENT 'x' DO ", 1),
+            target: every,
+        },
+        {
+            name:    "比对注释",
+            source:  strings.Replace(every, " DO ", " COMMENT 'x' DO ", 1),
+            target:  every,
+            options: Options{Comment: true},
+            changes: []string{"ALTER e"},
+            script:  []string{"ENABLE COMMENT 'x' DO DELETE FROM l;;"},
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            result := Compare(mustParseDDL(t, "CREATE TABLE l (id int);\n"+tt.source), mustParseDDL(t, "CREATE TABLE l (id int);\n"+tt.target), tt.options)
+
+            if changes := getObjectChanges(result); !slices.Equal(changes, tt.changes) {
+                t.Fatalf("changes = %q, want %q\n%s", changes, tt.changes, result.Script())
+            }
+
+            assertScript(t, result, tt.script...)
+        })
+    }
+}
+
+func TestCompareAutoIncrement(t *testing.T) {
+    const (
+        source = "CREATE TABLE t (id int NOT NULL AUTO_INCREMENT, PRIMARY KEY (id)) AUTO_INCREMENT=100; CREATE TABLE n (id int NOT NULL AUTO_INCREMENT, PRIMARY KEY (id)) AUTO_INCREMENT=5;"
+        lower  = "CREATE TABLE t (id int NOT NULL AUTO_INCREMENT, PRIMARY KEY (id)) AUTO_INCREMENT=10;"
+        higher = "CREATE TABLE t (id int NOT NULL AUTO_INCREMENT, PRIMARY KEY (id)) AUTO_INCREMENT=1000;"
+    )
+
+    tests := []struct {
+        name          string
+        autoIncrement AutoIncrement
+        target        string
+        kinds         []string
+        create        string
+    }{
+        {"不比对", AutoIncrementNone, lower, nil, "PRIMARY KEY (`id`)\n) ENGINE=InnoDB DEFAULT CHARSET"},
+        {"只在新建表时指定", AutoIncrementCreate, lower, nil, ") ENGINE=InnoDB AUTO_INCREMENT=5 DEFAULT CHARSET"},
+        {"调大", AutoIncrementRaise, lower, []string{"TABLE_OPTION_CHANGED"}, ") ENGINE=InnoDB AUTO_INCREMENT=5 DEFAULT CHARSET"},
+        {"不调小", AutoIncrementRaise, higher, nil, ") ENGINE=InnoDB AUTO_INCREMENT=5 DEFAULT CHARSET"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            result := Compare(mustParseDDL(t, source), mustParseDDL(t, tt.target), Options{AutoIncrement: tt.autoIncrement})
+
+            if kinds := getSpecKinds(result); !slices.Equal(kinds, tt.kinds) {
+                t.Fatalf("specs = %v, want %v\n%s", kinds, tt.kinds, result.Script())
+            }
+
+            assertScript(t, result, tt.create)
+
+            if tt.kinds != nil {
+                assertScript(t, result, "ALTER TABLE `t`\n  AUTO_INCREMENT=100;")
+            }
+        })
+    }
+}
+
+func TestCompareFulltextSpatial(t *testing.T) {
+    tests := []struct {
+        name   string
+        source string
+        target string
+        kinds  []string
+        script string
+    }{
+        {
+            name:   "新建表",
+            source: "CREATE TABLE t (a text, p point NOT NULL SRID 4326, FULLTEXT KEY ft (a) WITH PARSER ngram, SPATIAL KEY sp (p));",
+            target: "CREATE TABLE x (id int);",
+            script: "  `p` point SRID 4326 NOT NULL,\n  FULLTEXT KEY `ft` (`a`) WITH PARSER `ngram`,\n  SPATIAL KEY `sp` (`p`)\n) ENGINE=InnoDB",
+        },
+        {
+            name:   "新增索引",
+            source: "CREATE TABLE t (a text, p point NOT NULL SRID 4326, FULLTEXT KEY ft (a), SPATIAL KEY sp (p));",
+            target: "CREATE TABLE t (a text, p point NOT NULL SRID 4326);",
+            kinds:  []string{"INDEX_ADDED", "INDEX_ADDED"},
+            script: "ALTER TABLE `t`\n  ADD FULLTEXT KEY `ft` (`a`),\n  ADD SPATIAL KEY `sp` (`p`);",
+        },
+        {
+            name:   "修改解析器",
+            source: "CREATE TABLE t (a text, FULLTEXT KEY ft (a) WITH PARSER ngram);",
+            target: "CREATE TABLE t (a text, FULLTEXT KEY ft (a));",
+            kinds:  []string{"INDEX_MODIFIED"},
+            script: "DROP INDEX `ft`,\n  ADD FULLTEXT KEY `ft` (`a`) WITH PARSER `ngram`;",
+        },
+        {
+            name:   "普通索引改为全文索引",
+            source: "CREATE TABLE t (a varchar(100), FULLTEXT KEY k (a));",
+            target: "CREATE TABLE t (a varchar(100), KEY k (a));",
+            kinds:  []string{"INDEX_MODIFIED"},
+            script: "ADD FULLTEXT KEY `k` (`a`);",
+        },
+        {
+            name:   "修改 SRID",
+            source: "CREATE TABLE t (p point NOT NULL SRID 4326);",
+            target: "CREATE TABLE t (p point NOT NULL SRID 0);",
+            kinds:  []string{"COLUMN_MODIFIED"},
+            script: "MODIFY COLUMN `p` point SRID 4326 NOT NULL FIRST;",
+        },
+        {
+            name:   "SRID 相同",
+            source: "CREATE TABLE t (p point NOT NULL SRID 4326, SPATIAL KEY sp (p));",
+            target: "CREATE TABLE t (`p` POINT NOT NULL /*!80003 SRID 4326 */, SPATIAL KEY `sp` (`p`));",
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            result := Compare(mustParseDDL(t, tt.source), mustParseDDL(t, tt.target), Options{})
+
+            if kinds := getSpecKinds(result); !slices.Equal(kinds, tt.kinds) {
+                t.Fatalf("specs = %v, want %v\n%s", kinds, tt.kinds, result.Script())
+            }
+
+            assertScript(t, result, tt.script)
+        })
+    }
+}
+
+func TestCompareTableOptions(t *testing.T) {
+    source := mustParseDDL(t, "CREATE TABLE t (a int) ROW_FORMAT=COMPRESSED KEY_BLOCK_SIZE=8 STATS_PERSISTENT=1 ENCRYPTION='Y';")
+    target := mustParseDDL(t, "CREATE TABLE t (a int) ROW_FORMAT=DYNAMIC;")
+
+    tests := []struct {
+        name         string
+        tableOptions []string
+        script       string
+        down         string
+    }{
+        {"不比对", nil, "", ""},
+        {
+            "默认选项",
+            DefaultTableOptions,
+            "ALTER TABLE `t`\n  ROW_FORMAT=COMPRESSED,\n  KEY_BLOCK_SIZE=8,\n  STATS_PERSISTENT=1;",
+            "ALTER TABLE `t`\n  ROW_FORMAT=DYNAMIC,\n  KEY_BLOCK_SIZE=0,\n  STATS_PERSISTENT=DEFAULT;",
+        },
+        {
+            "增加 ENCRYPTION",
+            TableOptions,
+            "ALTER TABLE `t`\n  ROW_FORMAT=COMPRESSED,\n  KEY_BLOCK_SIZE=8,\n  STATS_PERSISTENT=1,\n  ENCRYPTION='Y';",
+            "ALTER TABLE `t`\n  ROW_FORMAT=DYNAMIC,\n  KEY_BLOCK_SIZE=0,\n  STATS_PERSISTENT=DEFAULT,\n  ENCRYPTION='N';",
+        },
+        {"只比对 ROW_FORMAT", []string{TableOptionRowFormat}, "ALTER TABLE `t`\n  ROW_FORMAT=COMPRESSED;", "ALTER TABLE `t`\n  ROW_FORMAT=DYNAMIC;"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            result := Compare(source, target, Options{TableOptions: tt.tableOptions})
+
+            if got := getChangeStatements(result); got != tt.script {
+                t.Errorf("script = %q, want %q", got, tt.script)
+            }
+
+            if got := getChangeStatements(result.Down()); got != tt.down {
+                t.Errorf("down = %q, want %q", got, tt.down)
+            }
+        })
+    }
+
+    t.Run("新建表", func(t *testing.T) {
+        result := Compare(source, &Catalog{Schema: source.Schema}, Options{TableOptions: DefaultTableOptions})
+
+        assertScript(t, result, "COLLATE=utf8mb4_0900_ai_ci ROW_FORMAT=COMPRESSED KEY_BLOCK_SIZE=8 STATS_PERSISTENT=1;")
+    })
+}
+
+// getChangeStatements 返回各差异渲染的语句，不含 SET NAMES 与 SET FOREIGN_KEY_CHECKS。
+func getChangeStatements(result *Result) string {
+    var (
+        statements []string
+        renderer   = result.Renderer()
+    )
+
+    for _, change := range result.Changes {
+        statements = append(statements, renderer.Render(change)...)
+    }
+
+    return strings.Join(statements, "\n")
+}
+
+func TestCompareDatabase(t *testing.T) {
+    // 解析 DDL 得不到默认加密与只读，在这里补上。
+    withOptions := func(catalog *Catalog, encryption string, readOnly bool) *Catalog {
+        catalog.Schema.DefaultEncryption = sql.NullString{String: encryption, Valid: true}
+        catalog.Schema.ReadOnly = readOnly
+
+        return catalog
+    }
+
+    tests := []struct {
+        name    string
+        source  *Catalog
+        target  *Catalog
+        options Options
+        script  string
+        down    string
+    }{
+        {
+            name:    "字符集与排序规则",
+            source:  mustParseDDL(t, "CREATE DATABASE db COLLATE utf8mb4_bin; CREATE TABLE t (a int);"),
+            target:  mustParseDDL(t, "CREATE DATABASE db COLLATE utf8mb4_general_ci; CREATE TABLE t (a int);"),
+            options: Options{Database: true},
+            script:  "ALTER DATABASE `db` DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_bin;\nALTER TABLE `t`\n  CHARACTER SET=utf8mb4, COLLATE=utf8mb4_bin;",
+            down:    "ALTER DATABASE `db` DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci;\nALTER TABLE `t`\n  CHARACTER SET=utf8mb4, COLLATE=utf8mb4_general_ci;",
+        },
+        {
+            name:    "不比对库",
+            source:  mustParseDDL(t, "CREATE DATABASE db COLLATE utf8mb4_bin; CREATE TABLE t (a int);"),
+            target:  mustParseDDL(t, "CREATE DATABASE db COLLATE utf8mb4_general_ci; CREATE TABLE t (a int);"),
+            options: Options{},
+            script:  "ALTER TABLE `t`\n  CHARACTER SET=utf8mb4, COLLATE=utf8mb4_bin;",
+            down:    "ALTER TABLE `t`\n  CHARACTER SET=utf8mb4, COLLATE=utf8mb4_general_ci;",
+        },
+        {
+            // 设为只读最后执行，取消只读最先执行。
+            name:    "默认加密与只读",
+            source:  withOptions(mustParseDDL(t, "CREATE DATABASE db; CREATE TABLE t (a int);"), "YES", true),
+            target:  withOptions(mustParseDDL(t, "CREATE DATABASE db; CREATE TABLE t (a int, b int);"), "NO", false),
+            options: Options{Database: true},
+            script:  "ALTER DATABASE `db` /*!80016 DEFAULT ENCRYPTION='Y' */;\nALTER TABLE `t`\n  DROP COLUMN `b`;\nALTER DATABASE `db` READ ONLY = 1;",
+            down:    "ALTER DATABASE `db` READ ONLY = 0;\nALTER DATABASE `db` /*!80016 DEFAULT ENCRYPTION='N' */;\nALTER TABLE `t`\n  ADD COLUMN `b` int DEFAULT NULL AFTER `a`;",
+        },
+        {
+            name:    "新建库",
+            source:  mustParseDDL(t, "CREATE DATABASE db COLLATE utf8mb4_bin; CREATE TABLE t (a int);"),
+            target:  MissingCatalog("db2"),
+            options: Options{},
+            script:  "CREATE DATABASE IF NOT EXISTS `db2` DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_bin;\nUSE `db2`;\nCREATE TABLE IF NOT EXISTS `t` (\n  `a` int DEFAULT NULL\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;",
+            down:    "DROP TABLE IF EXISTS `t`;",
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            result := Compare(tt.source, tt.target, tt.options)
+
+            if got := getChangeStatements(result); got != tt.script {
+                t.Errorf("script = %q, want %q", got, tt.script)
+            }
+
+            if got := getChangeStatements(result.Down()); got != tt.down {
+                t.Errorf("down = %q, want %q", got, tt.down)
+            }
+        })
+    }
+}
+
+func TestCompareVisibility(t *testing.T) {
+    tests := []struct {
+        name   string
+        source string
+        target string
+        script string
+        down   string
+    }{
+        {
+            name:   "只有可见性不同",
+            source: "a int, b int INVISIBLE, KEY ia (a) INVISIBLE",
+            target: "a int, b int, KEY ia (a)",
+            script: "ALTER TABLE `t`\n  ALTER COLUMN `b` SET INVISIBLE,\n  ALTER INDEX `ia` INVISIBLE;",
+            down:   "ALTER TABLE `t`\n  ALTER COLUMN `b` SET VISIBLE,\n  ALTER INDEX `ia` VISIBLE;",
+        },
+        {
+            name:   "同时有其他差异",
+            source: "a int, b bigint INVISIBLE, KEY ia (a, b) INVISIBLE",
+            target: "a int, b int, KEY ia (a)",
+            script: "ALTER TABLE `t`\n  MODIFY COLUMN `b` bigint DEFAULT NULL /*!80023 INVISIBLE */ AFTER `a`,\n  DROP INDEX `ia`,\n  ADD KEY `ia` (`a`,`b`) /*!80000 INVISIBLE */;",
+            down:   "ALTER TABLE `t`\n  MODIFY COLUMN `b` int DEFAULT NULL AFTER `a`,\n  DROP INDEX `ia`,\n  ADD KEY `ia` (`a`);",
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            source := mustParseDDL(t, "CREATE TABLE t ("+tt.source+");")
+            target := mustParseDDL(t, "CREATE TABLE t ("+tt.target+");")
+
+            result := Compare(source, target, Options{})
+
+            if kinds := getSpecKinds(result); !slices.Equal(kinds, []string{"COLUMN_MODIFIED", "INDEX_MODIFIED"}) {
+                t.Fatalf("specs = %v, want [COLUMN_MODIFIED INDEX_MODIFIED]", kinds)
+            }
+
+            if got := getChangeStatements(result); got != tt.script {
+                t.Errorf("script = %q, want %q", got, tt.script)
+            }
+
+            if got := getChangeStatements(result.Down()); got != tt.down {
+                t.Errorf("down = %q, want %q", got, tt.down)
+            }
+        })
+    }
+
+    t.Run("新建表", func(t *testing.T) {
+        source := mustParseDDL(t, "CREATE TABLE t (a int, b int INVISIBLE, KEY ia (a) INVISIBLE);")
+
+        assertScript(t, Compare(source, &Catalog{Schema: source.Schema}, Options{}), "`b` int DEFAULT NULL /*!80023 INVISIBLE */,\n  KEY `ia` (`a`) /*!80000 INVISIBLE */\n)")
+    })
+}
+
+func TestCompareGeneratedColumn(t *testing.T) {
+    tests := []struct {
+        name     string
+        source   string
+        target   string
+        modified bool
+    }{
+        {"写法不同", "CONCAT(`a`, ' x')", "concat(a,_utf8mb4' x')", false},
+        {"字符串中的空白", "CONCAT(a, 'x')", "CONCAT(a, ' x')", true},
+        {"字符串大小写", "CONCAT(a, 'X')", "CONCAT(a, 'x')", true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            source := mustParseDDL(t, "CREATE TABLE t (a varchar(10), b varchar(20) AS ("+tt.source+") VIRTUAL);")
+            target := mustParseDDL(t, "CREATE TABLE t (a varchar(10), b varchar(20) AS ("+tt.target+") VIRTUAL);")
+
+            result := Compare(source, target, Options{})
+
+            if modified := slices.Contains(getSpecKinds(result), "COLUMN_MODIFIED"); modified != tt.modified {
+                t.Fatalf("COLUMN_MODIFIED = %v, want %v, specs %v", modified, tt.modified, getSpecKinds(result))
+            }
+
+            if tt.modified {
+                assertScript(t, result, "MODIFY COLUMN `b` varchar(20) GENERATED ALWAYS AS (")
+            }
+        })
+    }
+}
+
+func TestCompareGenerationType(t *testing.T) {
+    tests := []struct {
+        name   string
+        source string
+        target string
+        kinds  []string
+        script []string
+    }{
+        {
+            name:   "修改虚拟列表达式",
+            source: "b int AS (a + 2) VIRTUAL",
+            target: "b int AS (a + 1) VIRTUAL",
+            kinds:  []string{"COLUMN_MODIFIED"},
+            script: []string{"MODIFY COLUMN `b` int GENERATED ALWAYS AS ((`a`+2)) VIRTUAL AFTER `a`"},
+        },
+        {
+            name:   "修改存储列表达式",
+            source: "b int AS (a + 2) STORED",
+            target: "b int AS (a + 1) STORED",
+            kinds:  []string{"COLUMN_MODIFIED"},
+            script: []string{"MODIFY COLUMN `b` int GENERATED ALWAYS AS ((`a`+2)) STORED AFTER `a`"},
+        },
+        {
+            name:   "虚拟列改为存储列",
+            source: "b int AS (a + 1) STORED, KEY ib (b)",
+            target: "b int AS (a + 1) VIRTUAL, KEY ib (b)",
+            kinds:  []string{"COLUMN_MODIFIED", "INDEX_MODIFIED"},
+            script: []string{"DROP COLUMN `b`,\n  ADD COLUMN `b` int GENERATED ALWAYS AS ((`a`+1)) STORED AFTER `a`", "DROP INDEX `ib`,\n  ADD KEY `ib` (`b`)"},
+        },
+        {
+            name:   "普通列改为虚拟列",
+            source: "b int AS (a + 1) VIRTUAL",
+            target: "b int",
+            kinds:  []string{"COLUMN_MODIFIED"},
+            script: []string{"DROP COLUMN `b`,\n  ADD COLUMN `b` int GENERATED ALWAYS AS ((`a`+1)) VIRTUAL AFTER `a`"},
+        },
+        {
+            name:   "新增生成列",
+            source: "b int AS (a + 1) VIRTUAL NOT NULL",
+            target: "c int",
+            kinds:  []string{"COLUMN_DROPPED", "COLUMN_ADDED"},
+            script: []string{"ADD COLUMN `b` int GENERATED ALWAYS AS ((`a`+1)) VIRTUAL NOT NULL AFTER `a`"},
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            source := mustParseDDL(t, "CREATE TABLE t (a int, "+tt.source+");")
+            target := mustParseDDL(t, "CREATE TABLE t (a int, "+tt.target+");")
+
+            result := Compare(source, target, Options{})
+
+            if kinds := getSpecKinds(result); !slices.Equal(kinds, tt.kinds) {
+                t.Fatalf("specs = %v, want %v", kinds, tt.kinds)
+            }
+
+            assertScript(t, result, tt.script...)
+        })
+    }
+}
+
+func TestCompareCheck(t *testing.T) {
+    tests := []struct {
+        name   string
+        source string
+        target string
+        kinds  []string
+        script []string
+    }{
+        {
+            name:   "写法不同",
+            source: "CONSTRAINT c1 CHECK (status IN ('A B'))",
+            target: "CONSTRAINT c1 CHECK ((`status` in (_utf8mb4'A B')))",
+        },
+        {
+            name:   "字符串中的空白与大小写",
+            source: "CONSTRAINT c1 CHECK (status IN ('ab'))",
+            target: "CONSTRAINT c1 CHECK (status IN ('A B'))",
+            kinds:  []string{"CHECK_MODIFIED"},
+            script: []string{"ALTER TABLE `t` DROP CHECK `c1`;", "ADD CONSTRAINT `c1` CHECK ((`status` in ('ab')))"},
+        },
+        {
+            name:   "是否强制检查",
+            source: "CONSTRAINT c1 CHECK (status IN ('ab')) NOT ENFORCED",
+            target: "CONSTRAINT c1 CHECK (status IN ('ab'))",
+            kinds:  []string{"CHECK_MODIFIED"},
+            script: []string{"ALTER CHECK `c1` NOT ENFORCED"},
+        },
+        {
+            name:   "自动命名的约束按表达式比对",
+            source: "CHECK (status IN ('ab'))",
+            target: "CHECK (status IN ('AB'))",
+            kinds:  []string{"CHECK_DROPPED", "CHECK_ADDED"},
+            script: []string{"DROP CHECK `t_chk_1`", "ADD CHECK ((`status` in ('ab')))"},
+        },
+        {
+            name:   "新增",
+            source: "CONSTRAINT c1 CHECK (status <> '') NOT ENFORCED",
+            target: "KEY k1 (status)",
+            kinds:  []string{"INDEX_DROPPED", "CHECK_ADDED"},
+            script: []string{"ADD CONSTRAINT `c1` CHECK ((`status`!='')) NOT ENFORCED"},
+        },
+        {
+            name:   "删除",
+            source: "KEY k1 (status)",
+            target: "CONSTRAINT c1 CHECK (status <> '')",
+            kinds:  []string{"INDEX_ADDED", "CHECK_DROPPED"},
+            script: []string{"DROP CHECK `c1`"},
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            source := mustParseDDL(t, "CREATE TABLE t (status varchar(10), "+tt.source+");")
+            target := mustParseDDL(t, "CREATE TABLE t (status varchar(10), "+tt.target+");")
+
+            result := Compare(source, target, Options{})
+
+            if kinds := getSpecKinds(result); !slices.Equal(kinds, tt.kinds) {
+                t.Fatalf("specs = %v, want %v", kinds, tt.kinds)
+            }
+
+            assertScript(t, result, tt.script...)
+        })
+    }
+    t.Run("新建表", func(t *testing.T) {
+        source := mustParseDDL(t, "CREATE TABLE t (status varchar(10), CONSTRAINT c1 CHECK (status <> '') NOT ENFORCED);")
+
+        assertScript(t, Compare(source, &Catalog{Schema: source.Schema}, Options{}), " DEFAULT NULL,\n  CONSTRAINT `c1` CHECK ((`status`!='')) NOT ENFORCED\n) ENGINE=InnoDB")
+    })
+}
+
+func TestCompareFunctionalIndex(t *testing.T) {
+    tests := []struct {
+        name     string
+        source   string
+        target   string
+        modified bool
+    }{
+        {"写法不同", "(JSON_UNQUOTE(JSON_EXTRACT(`doc`, '$.name')))", "((json_unquote(json_extract(`doc`,_utf8mb4'$.name'))))", false},
+        {"字符串大小写", "(JSON_UNQUOTE(JSON_EXTRACT(doc, '$.Name')))", "(JSON_UNQUOTE(JSON_EXTRACT(doc, '$.name')))", true},
+        {"字符串中的空白", "(CONCAT(doc, 'a b'))", "(CONCAT(doc, 'ab'))", true},
+        {"降序", "(LOWER(doc)) DESC", "(LOWER(doc))", true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            source := mustParseDDL(t, "CREATE TABLE t (doc varchar(100), KEY k1 ("+tt.source+"));")
+            target := mustParseDDL(t, "CREATE TABLE t (doc varchar(100), KEY k1 ("+tt.target+"));")
+
+            result := Compare(source, target, Options{})
+
+            if modified := slices.Contains(getSpecKinds(result), "INDEX_MODIFIED"); modified != tt.modified {
+                t.Fatalf("INDEX_MODIFIED = %v, want %v, specs %v", modified, tt.modified, getSpecKinds(result))
+            }
+
+            if tt.modified {
+                assertScript(t, result, "DROP INDEX `k1`", "ADD KEY `k1` (")
+            }
+        })
+    }
+}
+
+func TestCompareIndexParts(t *testing.T) {
+    tests := []struct {
+        name   string
+        source string
+        target string
+        kinds  []string
+        script string
+    }{
+        {"降序", "KEY i (a, b DESC)", "KEY i (a, b)", []string{"INDEX_MODIFIED"}, "DROP INDEX `i`,\n  ADD KEY `i` (`a`,`b` DESC);"},
+        {"前缀", "KEY i (c(10))", "KEY i (c(20))", []string{"INDEX_MODIFIED"}, "ADD KEY `i` (`c`(10));"},
+        {"多值索引", "KEY mv ((CAST(j->'$.ids' AS UNSIGNED ARRAY)))", "", []string{"INDEX_ADDED"}, "ADD KEY `mv` ((cast(json_extract(`j`, '$.ids') as unsigned array)));"},
+        {"多值索引写法不同", "KEY mv ((CAST(j->'$.ids' AS UNSIGNED ARRAY)))", "KEY `mv` ((cast(json_extract(`j`,_utf8mb4'$.ids') as unsigned array)))", nil, ""},
+        {"函数索引与普通列混合", "KEY i (a, (b + 1) DESC)", "", []string{"INDEX_ADDED"}, "ADD KEY `i` (`a`,((`b`+1)) DESC);"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            const columns = "CREATE TABLE t (a int, b int, c varchar(50), j json"
+
+            target := columns + ");"
+
+            if tt.target != "" {
+                target = columns + ", " + tt.target + ");"
+            }
+
+            result := Compare(mustParseDDL(t, columns+", "+tt.source+");"), mustParseDDL(t, target), Options{})
+
+            if kinds := getSpecKinds(result); !slices.Equal(kinds, tt.kinds) {
+                t.Fatalf("specs = %v, want %v\n%s", kinds, tt.kinds, result.Script())
+            }
+
+            assertScript(t, result, tt.script)
+        })
+    }
+}
+
+func TestCompareColumnDefault(t *testing.T) {
+    tests := []struct {
+        name   string
+        source string
+        target string
+        want   string
+    }{
+        {"表达式", "a varchar(36) DEFAULT (uuid())", "a varchar(36) DEFAULT NULL", "MODIFY COLUMN `a` varchar(36) DEFAULT (uuid()) AFTER `id`"},
+        {"JSON 表达式", "a json DEFAULT (json_array())", "a json", "MODIFY COLUMN `a` json DEFAULT (json_array()) AFTER `id`"},
+        {"当前时间与更新", "a timestamp(3) NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3)", "a timestamp(3) NULL DEFAULT NULL", "MODIFY COLUMN `a` timestamp(3) NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3) AFTER `id`"},
+        {"位", "a bit(3) DEFAULT b'101'", "a bit(3) DEFAULT b'1'", "MODIFY COLUMN `a` bit(3) DEFAULT b'101' AFTER `id`"},
+        {"引号", "a varchar(10) DEFAULT 'it''s'", "a varchar(10) DEFAULT 'x'", "MODIFY COLUMN `a` varchar(10) DEFAULT 'it\\'s' AFTER `id`"},
+        {"二进制", "a varbinary(10) DEFAULT 'ab'", "a varbinary(10)", "MODIFY COLUMN `a` varbinary(10) DEFAULT X'6162' AFTER `id`"},
+        {"同义词", "a datetime DEFAULT now()", "a datetime DEFAULT CURRENT_TIMESTAMP", ""},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            source := mustParseDDL(t, "CREATE TABLE t (id int, "+tt.source+");")
+            target := mustParseDDL(t, "CREATE TABLE t (id int, "+tt.target+");")
+
+            result := Compare(source, target, Options{})
+
+            if tt.want == "" {
+                if kinds := getSpecKinds(result); len(kinds) != 0 {
+                    t.Fatalf("specs = %v, want none", kinds)
+                }
+
+                return
+            }
+
+            assertScript(t, result, tt.want)
+        })
+    }
+}
+
+func TestCompareStringDefaultNow(t *testing.T) {
+    source := mustParseDDL(t, "CREATE TABLE t (a varchar(20) NOT NULL DEFAULT 'now', b datetime DEFAULT CURRENT_TIMESTAMP);")
+    target := mustParseDDL(t, "CREATE TABLE t (a varchar(20) NOT NULL DEFAULT 'current_timestamp', b datetime DEFAULT NOW());")
+
+    result := Compare(source, target, Options{})
+
+    if kinds := getSpecKinds(result); !slices.Equal(kinds, []string{"COLUMN_MODIFIED"}) {
+        t.Fatalf("specs = %v, want [COLUMN_MODIFIED]", kinds)
+    }
+
+    assertScript(t, result, "MODIFY COLUMN `a` varchar(20) NOT NULL DEFAULT 'now'")
+}
+
+func TestComparePartitionBound(t *testing.T) {
+    tests := []struct {
+        name string
+        a    string
+        b    string
+        want int
+    }{
+        {"相同", "739282", "739282", 0},
+        {"整数按数值比对", "9", "10", -1},
+        {"MAXVALUE 最大", "MAXVALUE", "739282", 1},
+        {"日期", "'2024-03-01'", "'2024-02-01'", 1},
+        {"多列", "'2024-01-01',10", "'2024-01-01',MAXVALUE", -1},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := comparePartitionBound(tt.a, tt.b); got != tt.want {
+                t.Errorf("comparePartitionBound(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+            }
+        })
+    }
+}
@@ -0,0 +1,983 @@
+package mysqldiff
+
+import (
+    "slices"
+    "sort"
+    "strconv"
+    "strings"
+
+    "github.com/samber/lo"
+)
+
+// differ 一次比对的状态。
+type differ struct {
+    options Options
+    source  *Catalog
+    target  *Catalog
+
+    // renamedTables 重命名的表（新表名 => 旧表名）。
+    renamedTables map[string]string
+
+    result *Result
+}
+
+// renameTables 检测重命名的表。
+//
+// 优先使用重命名提示；检测重命名时，列与索引都相同的删除表与新建表视为重命名，有多个候选时不视为重命名。
+func (d *differ) renameTables() {
+    d.renamedTables = make(map[string]string)
+
+    var (
+        addedTables   []Table
+        droppedTables []Table
+    )
+
+    for _, sourceTable := range d.source.Tables {
+        if _, ok := d.target.Table(sourceTable.TableName); !ok && sourceTable.TableType == "BASE TABLE" {
+            addedTables = append(addedTables, sourceTable)
+        }
+    }
+
+    for _, targetTable := range d.target.Tables {
+        if _, ok := d.source.Table(targetTable.TableName); !ok && targetTable.TableType == "BASE TABLE" {
+            droppedTables = append(droppedTables, targetTable)
+        }
+    }
+
+    renamedFrom := make(map[string]bool)
+
+    // Hints ...
+    for _, droppedTable := range droppedTables {
+        newName, ok := d.options.Hints.Tables[droppedTable.TableName]
+
+        if !ok {
+            continue
+        }
+
+        for _, addedTable := range addedTables {
+            if addedTable.TableName == newName {
+                d.renamedTables[newName] = droppedTable.TableName
+                renamedFrom[droppedTable.TableName] = true
+            }
+        }
+    }
+
+    if !d.options.Rename {
+        return
+    }
+
+    // 新表名 => 候选旧表名，旧表名 => 候选新表名。
+    oldCandidates := make(map[string][]string)
+    newCandidates := make(map[string][]string)
+
+    for _, addedTable := range addedTables {
+        if _, ok := d.renamedTables[addedTable.TableName]; ok {
+            continue
+        }
+
+        for _, droppedTable := range droppedTables {
+            if !renamedFrom[droppedTable.TableName] && d.sameTable(addedTable.TableName, droppedTable.TableName) {
+                oldCandidates[addedTable.TableName] = append(oldCandidates[addedTable.TableName], droppedTable.TableName)
+                newCandidates[droppedTable.TableName] = append(newCandidates[droppedTable.TableName], addedTable.TableName)
+            }
+        }
+    }
+
+    for _, addedTable := range addedTables {
+        oldNames := oldCandidates[addedTable.TableName]
+
+        if len(oldNames) == 1 && len(newCandidates[oldNames[0]]) == 1 {
+            d.renamedTables[addedTable.TableName] = oldNames[0]
+        }
+    }
+}
+
+// sameTable 源表与目标表的列与索引是否相同。
+func (d *differ) sameTable(sourceTableName string, targetTableName string) bool {
+    sourceColumnsPos := make(map[int]Column)
+    targetColumnsPos := make(map[int]Column)
+
+    for _, sourceColumn := range d.source.TableColumns(sourceTableName) {
+        sourceColumnsPos[sourceColumn.OrdinalPosition] = sourceColumn
+    }
+
+    for _, targetColumn := range d.target.TableColumns(targetTableName) {
+        targetColumnsPos[targetColumn.OrdinalPosition] = targetColumn
+    }
+
+    if len(sourceColumnsPos) <= 0 || !compareColumns(sourceColumnsPos, targetColumnsPos, d.options.Comment) {
+        return false
+    }
+
+    sourceStatisticsDataMap := make(map[string]map[int]Statistic)
+    targetStatisticsDataMap := make(map[string]map[int]Statistic)
+
+    for _, sourceIndex := range d.source.TableIndexes(sourceTableName) {
+        sourceStatisticsDataMap[sourceIndex.Name] = sourceIndex.Statistics
+    }
+
+    for _, targetIndex := range d.target.TableIndexes(targetTableName) {
+        targetStatisticsDataMap[targetIndex.Name] = targetIndex.Statistics
+    }
+
+    return compareStatistics(sourceStatisticsDataMap, targetStatisticsDataMap)
+}
+
+// DROP TABLE Or DROP VIEW...
+func (d *differ) drop() {
+    renamedFrom := make(map[string]bool)
+
+    for _, oldName := range d.renamedTables {
+        renamedFrom[oldName] = true
+    }
+
+    for _, targetTable := range d.target.Tables {
+        if renamedFrom[targetTable.TableName] {
+            continue
+        }
+
+        if _, ok := d.source.Table(targetTable.TableName); !ok {
+            switch targetTable.TableType {
+            case "BASE TABLE":
+                d.addChange(&TableChange{Type: ChangeDrop, Table: targetTable})
+            case "VIEW":
+                d.addChange(&ViewChange{Type: ChangeDrop, Name: targetTable.TableName})
+            }
+        }
+    }
+}
+
+// SQL DIFF ...
+func (d *differ) diff(sourceTable Table) {
+    switch sourceTable.TableType {
+    case "BASE TABLE":
+        if _, ok := d.target.Table(sourceTable.TableName); ok {
+            d.alterTable(sourceTable)
+        } else if oldName, ok := d.renamedTables[sourceTable.TableName]; ok {
+            d.renameTable(sourceTable, oldName)
+        } else {
+            d.createTable(sourceTable)
+        }
+    case "VIEW":
+        d.createView(sourceTable)
+    }
+}
+
+func (d *differ) addChange(change Change) {
+    d.result.Changes = append(d.result.Changes, change)
+}
+
+// CREATE TABLE ...
+func (d *differ) createTable(sourceTable Table) {
+    sourceColumnData := d.source.TableColumns(sourceTable.TableName)
+
+    if len(sourceColumnData) <= 0 {
+        return
+    }
+
+    change := &TableChange{
+        Type:       ChangeCreate,
+        Table:      sourceTable,
+        Columns:    sourceColumnData,
+        Indexes:    d.source.TableIndexes(sourceTable.TableName),
+        Partitions: d.source.TablePartitions(sourceTable.TableName),
+    }
+
+    if d.options.Foreign {
+        change.ForeignKeys = d.source.TableForeignKeys(sourceTable.TableName)
+    }
+
+    change.Checks = d.source.TableChecks(sourceTable.TableName)
+
+    d.addChange(change)
+}
+
+// ALTER TABLE ...
+func (d *differ) alterTable(sourceTable Table) {
+    targetTable, _ := d.target.Table(sourceTable.TableName)
+
+    if specs := d.alterSpecs(sourceTable, targetTable); len(specs) > 0 {
+        d.addChange(&TableChange{
+            Type:  ChangeAlter,
+            Table: sourceTable,
+            Specs: specs,
+        })
+    }
+}
+
+// RENAME TABLE ... ALTER TABLE ...
+func (d *differ) renameTable(sourceTable Table, oldName string) {
+    targetTable, _ := d.target.Table(oldName)
+
+    d.addChange(&TableChange{
+        Type:    ChangeRename,
+        Table:   sourceTable,
+        OldName: oldName,
+        Specs:   d.alterSpecs(sourceTable, targetTable),
+    })
+}
+
+// alterSpecs 返回源表与目标表的各项差异。
+func (d *differ) alterSpecs(sourceTable Table, targetTable Table) []AlterSpec {
+    // ALTER LIST ...
+    var specs []AlterSpec
+
+    columnSpecs := d.alterColumns(
+        sourceTable.TableName,
+        d.source.TableColumns(sourceTable.TableName),
+        d.target.TableColumns(targetTable.TableName),
+    )
+
+    specs = append(specs, columnSpecs...)
+
+    // ADD KEY AND DROP INDEX ...
+    specs = append(specs, d.alterIndexes(
+        d.source.TableIndexes(sourceTable.TableName),
+        d.target.TableIndexes(targetTable.TableName),
+        getRecreatedColumns(columnSpecs),
+        getRenamedColumns(columnSpecs),
+    )...)
+
+    if d.options.Foreign {
+        specs = append(specs, d.alterForeignKeys(
+            d.source.TableForeignKeys(sourceTable.TableName),
+            d.target.TableForeignKeys(targetTable.TableName),
+        )...)
+    }
+
+    // DROP CHECK ... ADD CHECK ... ALTER CHECK ...
+    specs = append(specs, d.alterChecks(
+        d.source.TableChecks(sourceTable.TableName),
+        d.target.TableChecks(targetTable.TableName),
+    )...)
+
+    specs = append(specs, d.alterTableOptions(sourceTable, targetTable)...)
+
+    // PARTITION BY ... ADD PARTITION ... DROP PARTITION ...
+    specs = append(specs, d.alterPartitions(
+        d.source.TablePartitions(sourceTable.TableName),
+        d.target.TablePartitions(targetTable.TableName),
+    )...)
+
+    return specs
+}
+
+// DROP COLUMN ... ADD COLUMN ... MODIFY COLUMN ... CHANGE COLUMN ...
+func (d *differ) alterColumns(tableName string, sourceColumnData []Column, targetColumnData []Column) []AlterSpec {
+    var specs []AlterSpec
+
+    if len(sourceColumnData) <= 0 || len(targetColumnData) <= 0 {
+        return specs
+    }
+
+    sourceColumns := make(map[string]Column)
+    targetColumns := make(map[string]Column)
+    originColumns := make(map[string]Column)
+    sourceColumnsPos := make(map[int]Column)
+    targetColumnsPos := make(map[int]Column)
+
+    for _, sourceColumn := range sourceColumnData {
+        sourceColumns[sourceColumn.ColumnName] = sourceColumn
+        sourceColumnsPos[sourceColumn.OrdinalPosition] = sourceColumn
+    }
+
+    for _, targetColumn := range targetColumnData {
+        targetColumns[targetColumn.ColumnName] = targetColumn
+        originColumns[targetColumn.ColumnName] = targetColumn
+        targetColumnsPos[targetColumn.OrdinalPosition] = targetColumn
+    }
+
+    if compareColumns(sourceColumnsPos, targetColumnsPos, d.options.Comment) {
+        return specs
+    }
+
+    // 重命名的列按新列名比对。
+    renamedColumns := d.renameColumns(tableName, sourceColumnData, targetColumnData)
+
+    for oldName, newName := range renamedColumns {
+        targetColumn := targetColumns[oldName]
+        targetColumn.ColumnName = newName
+
+        delete(targetColumns, oldName)
+        targetColumns[newName] = targetColumn
+        originColumns[newName] = originColumns[oldName]
+    }
+
+    // DROP COLUMN ...
+    for _, targetColumn := range targetColumnData {
+        if _, ok := renamedColumns[targetColumn.ColumnName]; ok {
+            continue
+        }
+
+        if _, ok := sourceColumns[targetColumn.ColumnName]; !ok {
+            resetCalcPosition(targetColumn.ColumnName, targetColumns[targetColumn.ColumnName].OrdinalPosition, targetColumns, StatusDrop)
+
+            specs = append(specs, ColumnDropped{Column: targetColumn})
+        }
+    }
+
+    // ADD COLUMN ...
+    for _, sourceColumn := range sourceColumnData {
+        if _, ok := targetColumns[sourceColumn.ColumnName]; !ok {
+            specs = append(specs, ColumnAdded{
+                Column: sourceColumn,
+                After:  getColumnAfter(sourceColumn.OrdinalPosition, sourceColumnsPos),
+            })
+
+            resetCalcPosition(sourceColumn.ColumnName, sourceColumn.OrdinalPosition, targetColumns, StatusAdd)
+        }
+    }
+
+    renamedTo := make(map[string]bool)
+
+    for _, newName := range renamedColumns {
+        renamedTo[newName] = true
+    }
+
+    // MODIFY COLUMN ... CHANGE COLUMN ...
+    for _, sourceColumn := range sourceColumnData {
+        columnName := sourceColumn.ColumnName
+
+        if _, ok := targetColumns[columnName]; ok {
+            if renamedTo[columnName] {
+                specs = append(specs, ColumnRenamed{
+                    From:  originColumns[columnName],
+                    To:    sourceColumn,
+                    After: getColumnAfter(sourceColumn.OrdinalPosition, sourceColumnsPos),
+                })
+
+                resetCalcPosition(columnName, sourceColumn.OrdinalPosition, targetColumns, StatusModify)
+            } else if !compareColumn(sourceColumn, targetColumns[columnName], d.options.Comment) {
+                specs = append(specs, ColumnModified{
+                    From:           originColumns[columnName],
+                    To:             sourceColumn,
+                    After:          getColumnAfter(sourceColumn.OrdinalPosition, sourceColumnsPos),
+                    VisibilityOnly: compareColumnDefinition(sourceColumn, targetColumns[columnName], d.options.Comment),
+                })
+
+                resetCalcPosition(columnName, sourceColumn.OrdinalPosition, targetColumns, StatusModify)
+            }
+        }
+    }
+
+    return specs
+}
+
+// renameColumns 返回重命名的列（旧列名 => 新列名）。
+//
+// 优先使用重命名提示；检测重命名时，位置、类型、是否为空、默认值、注释都相同的删除列与新增列视为重命名。
+func (d *differ) renameColumns(tableName string, sourceColumnData []Column, targetColumnData []Column) map[string]string {
+    renamedColumns := make(map[string]string)
+
+    var (
+        addedColumns   []Column
+        droppedColumns []Column
+        sourceColumns  = make(map[string]bool)
+        targetColumns  = make(map[string]bool)
+        renamedTo      = make(map[string]bool)
+    )
+
+    for _, sourceColumn := range sourceColumnData {
+        sourceColumns[sourceColumn.ColumnName] = true
+    }
+
+    for _, targetColumn := range targetColumnData {
+        targetColumns[targetColumn.ColumnName] = true
+
+        if !sourceColumns[targetColumn.ColumnName] {
+            droppedColumns = append(droppedColumns, targetColumn)
+        }
+    }
+
+    for _, sourceColumn := range sourceColumnData {
+        if !targetColumns[sourceColumn.ColumnName] {
+            addedColumns = append(addedColumns, sourceColumn)
+        }
+    }
+
+    // Hints ...
+    for _, droppedColumn := range droppedColumns {
+        if newName, ok := d.options.Hints.column(tableName, droppedColumn.ColumnName); ok && sourceColumns[newName] && !targetColumns[newName] {
+            renamedColumns[droppedColumn.ColumnName] = newName
+            renamedTo[newName] = true
+        }
+    }
+
+    if !d.options.Rename {
+        return renamedColumns
+    }
+
+    for _, droppedColumn := range droppedColumns {
+        if _, ok := renamedColumns[droppedColumn.ColumnName]; ok {
+            continue
+        }
+
+        for _, addedColumn := range addedColumns {
+            if renamedTo[addedColumn.ColumnName] {
+                continue
+            }
+
+            if addedColumn.OrdinalPosition == droppedColumn.OrdinalPosition &&
+                addedColumn.ColumnType == droppedColumn.ColumnType &&
+                addedColumn.IsNullable == droppedColumn.IsNullable &&
+                addedColumn.ColumnDefault == droppedColumn.ColumnDefault &&
+                addedColumn.ColumnComment == droppedColumn.ColumnComment {
+                renamedColumns[droppedColumn.ColumnName] = addedColumn.ColumnName
+                renamedTo[addedColumn.ColumnName] = true
+
+                break
+            }
+        }
+    }
+
+    return renamedColumns
+}
+
+// getRecreatedColumns 返回需要删除后重新添加的生成列。
+func getRecreatedColumns(specs []AlterSpec) map[string]bool {
+    recreatedColumns := make(map[string]bool)
+
+    for _, spec := range specs {
+        if s, ok := spec.(ColumnModified); ok && !canModifyGeneration(s.From, s.To) {
+            recreatedColumns[s.To.ColumnName] = true
+        }
+    }
+
+    return recreatedColumns
+}
+
+// getRenamedColumns 返回重命名的列（旧列名 => 新列名）。
+func getRenamedColumns(specs []AlterSpec) map[string]string {
+    renamedColumns := make(map[string]string)
+
+    for _, spec := range specs {
+        if s, ok := spec.(ColumnRenamed); ok {
+            renamedColumns[s.From.ColumnName] = s.To.ColumnName
+        }
+    }
+
+    return renamedColumns
+}
+
+// DROP INDEX ... ADD KEY ...
+//
+// 删除列时会从索引中去掉该列，recreatedColumns 中的列所在的索引需要重建；
+// CHANGE COLUMN 会同时修改索引中的列名，目标索引中 renamedColumns 中的列按新列名比对。
+func (d *differ) alterIndexes(sourceIndexes []Index, targetIndexes []Index, recreatedColumns map[string]bool, renamedColumns map[string]string) []AlterSpec {
+    var specs []AlterSpec
+
+    sourceStatisticsDataMap := make(map[string]map[int]Statistic)
+    targetStatisticsDataMap := make(map[string]map[int]Statistic)
+    originStatisticsDataMap := make(map[string]map[int]Statistic)
+
+    for _, sourceIndex := range sourceIndexes {
+        sourceStatisticsDataMap[sourceIndex.Name] = sourceIndex.Statistics
+    }
+
+    for _, targetIndex := range targetIndexes {
+        targetStatisticsDataMap[targetIndex.Name] = getRenamedStatistics(targetIndex.Statistics, renamedColumns)
+        originStatisticsDataMap[targetIndex.Name] = targetIndex.Statistics
+    }
+
+    if compareStatistics(sourceStatisticsDataMap, targetStatisticsDataMap) && len(recreatedColumns) <= 0 {
+        return specs
+    }
+
+    // DROP INDEX ...
+    for _, targetIndex := range targetIndexes {
+        if _, ok := sourceStatisticsDataMap[targetIndex.Name]; !ok {
+            specs = append(specs, IndexDropped{Index: targetIndex})
+        }
+    }
+
+    // DROP INDEX ... AND ADD KEY ...
+    for _, sourceIndex := range sourceIndexes {
+        if targetStatisticMap, ok := targetStatisticsDataMap[sourceIndex.Name]; ok {
+            recreated := lo.SomeBy(lo.Values(targetStatisticMap), func(statistic Statistic) bool {
+                return recreatedColumns[statistic.ColumnName]
+            })
+
+            if recreated || !compareStatisticsIndex(sourceIndex.Statistics, targetStatisticMap) {
+                specs = append(specs, IndexModified{
+                    From:           Index{Name: sourceIndex.Name, Statistics: originStatisticsDataMap[sourceIndex.Name]},
+                    To:             sourceIndex,
+                    VisibilityOnly: !recreated && isIndexVisibilityChanged(sourceIndex.Statistics, targetStatisticMap),
+                })
+            }
+        } else {
+            specs = append(specs, IndexAdded{Index: sourceIndex})
+        }
+    }
+
+    return specs
+}
+
+// DROP FOREIGN KEY ... ADD CONSTRAINT ...
+func (d *differ) alterForeignKeys(sourceForeignKeys []ForeignKey, targetForeignKeys []ForeignKey) []AlterSpec {
+    var specs []AlterSpec
+
+    sourceForeignKeyMap := make(map[string]ForeignKey)
+    targetForeignKeyMap := make(map[string]ForeignKey)
+
+    for _, sourceForeignKey := range sourceForeignKeys {
+        sourceForeignKeyMap[sourceForeignKey.Constraint.ConstraintName] = sourceForeignKey
+    }
+
+    for _, targetForeignKey := range targetForeignKeys {
+        targetForeignKeyMap[targetForeignKey.Constraint.ConstraintName] = targetForeignKey
+
+        if _, ok := sourceForeignKeyMap[targetForeignKey.Constraint.ConstraintName]; !ok {
+            specs = append(specs, ForeignKeyDropped{ForeignKey: targetForeignKey})
+        }
+    }
+
+    for _, sourceForeignKey := range sourceForeignKeys {
+        if targetForeignKey, ok := targetForeignKeyMap[sourceForeignKey.Constraint.ConstraintName]; ok {
+            if !compareForeignKey(sourceForeignKey, targetForeignKey) {
+                specs = append(specs, ForeignKeyModified{From: targetForeignKey, To: sourceForeignKey})
+            }
+        } else {
+            specs = append(specs, ForeignKeyAdded{ForeignKey: sourceForeignKey})
+        }
+    }
+
+    return specs
+}
+
+// DROP CHECK ... ADD CHECK ... ALTER CHECK ...
+//
+// 自动生成的约束名（如 t1_chk_1）的序号与定义顺序有关，这类约束按表达式比对，其余按名称比对。
+func (d *differ) alterChecks(sourceChecks []Check, targetChecks []Check) []AlterSpec {
+    var (
+        specs          []AlterSpec
+        targetCheckMap = make(map[string]Check)
+        matched        = make(map[string]bool) // 已比对的目标约束
+    )
+
+    for _, targetCheck := range targetChecks {
+        if !isGeneratedCheck(targetCheck) {
+            targetCheckMap[targetCheck.Constraint.ConstraintName] = targetCheck
+        }
+    }
+
+    var addedChecks []Check
+
+    for _, sourceCheck := range sourceChecks {
+        targetCheck, ok := targetCheckMap[sourceCheck.Constraint.ConstraintName]
+
+        if isGeneratedCheck(sourceCheck) {
+            targetCheck, ok = lo.Find(targetChecks, func(targetCheck Check) bool {
+                return isGeneratedCheck(targetCheck) && !matched[targetCheck.Constraint.ConstraintName] &&
+                    getNormalizedSchemaExpression(sourceCheck.CheckClause) == getNormalizedSchemaExpression(targetCheck.CheckClause)
+            })
+        }
+
+        if !ok {
+            addedChecks = append(addedChecks, sourceCheck)
+
+            continue
+        }
+
+        matched[targetCheck.Constraint.ConstraintName] = true
+
+        if !compareCheck(sourceCheck, targetCheck) {
+            specs = append(specs, CheckModified{From: targetCheck, To: sourceCheck})
+        }
+    }
+
+    for _, targetCheck := range targetChecks {
+        if !matched[targetCheck.Constraint.ConstraintName] {
+            specs = append(specs, CheckDropped{Check: targetCheck})
+        }
+    }
+
+    for _, addedCheck := range addedChecks {
+        specs = append(specs, CheckAdded{Check: addedCheck})
+    }
+
+    return specs
+}
+
+// ENGINE ... CHARACTER SET ... COMMENT ...
+func (d *differ) alterTableOptions(sourceTable Table, targetTable Table) []AlterSpec {
+    var specs []AlterSpec
+
+    // ENGINE
+    if sourceTable.ENGINE.Valid {
+        if sourceTable.ENGINE.String != targetTable.ENGINE.String {
+            specs = append(specs, TableOptionChanged{Name: "ENGINE", From: targetTable.ENGINE.String, To: sourceTable.ENGINE.String})
+        }
+    }
+
+    // CHARACTER SET,COLLATE
+    if sourceTable.TableCollation.Valid {
+        if sourceTable.TableCollation.String != targetTable.TableCollation.String {
+            specs = append(specs, TableOptionChanged{Name: "COLLATE", From: targetTable.TableCollation.String, To: sourceTable.TableCollation.String})
+        }
+    }
+
+    // COMMENT
+    if d.options.Comment {
+        if sourceTable.TableComment != targetTable.TableComment {
+            specs = append(specs, TableOptionChanged{Name: "COMMENT", From: targetTable.TableComment, To: sourceTable.TableComment})
+        }
+    }
+
+    // ROW_FORMAT、KEY_BLOCK_SIZE、STATS_PERSISTENT ... 只比对指定的扩展选项。
+    sourceOptions := getTableOptions(sourceTable)
+    targetOptions := getTableOptions(targetTable)
+
+    for _, name := range TableOptions {
+        // ALTER TABLE 会忽略 DATA DIRECTORY，只在新建表时指定。
+        if !lo.Contains(d.options.TableOptions, name) || name == TableOptionDataDirectory {
+            continue
+        }
+
+        if sourceOptions[name] != targetOptions[name] {
+            specs = append(specs, TableOptionChanged{Name: name, From: targetOptions[name], To: sourceOptions[name]})
+        }
+    }
+
+    // AUTO_INCREMENT 只调大，避免复用已分配的 ID。
+    if d.options.AutoIncrement == AutoIncrementRaise && sourceTable.AutoIncrement.Valid && targetTable.AutoIncrement.Valid {
+        if sourceTable.AutoIncrement.Int64 > targetTable.AutoIncrement.Int64 {
+            specs = append(specs, TableOptionChanged{
+                Name: "AUTO_INCREMENT",
+                From: strconv.FormatInt(targetTable.AutoIncrement.Int64, 10),
+                To:   strconv.FormatInt(sourceTable.AutoIncrement.Int64, 10),
+            })
+        }
+    }
+
+    return specs
+}
+
+// PARTITION BY ... REMOVE PARTITIONING ... ADD PARTITION ... DROP PARTITION ... REORGANIZE PARTITION ...
+func (d *differ) alterPartitions(sourcePartitions []Partition, targetPartitions []Partition) []AlterSpec {
+    if len(sourcePartitions) <= 0 && len(targetPartitions) <= 0 {
+        return nil
+    }
+
+    if len(sourcePartitions) <= 0 {
+        return []AlterSpec{PartitioningRemoved{From: targetPartitions}}
+    }
+
+    if getPartitionScheme(sourcePartitions) != getPartitionScheme(targetPartitions) {
+        return []AlterSpec{PartitioningChanged{From: targetPartitions, To: sourcePartitions}}
+    }
+
+    sourceDefinitions := getPartitionDefinitions(sourcePartitions)
+    targetDefinitions := getPartitionDefinitions(targetPartitions)
+
+    // HASH 与 KEY 分区的分区数已在分区方式中比对。
+    if !isPartitionRange(sourceDefinitions[0].PartitionMethod.String) {
+        return nil
+    }
+
+    var (
+        specs     []AlterSpec
+        dropped   []Partition
+        remaining []Partition
+        names     = make(map[string]Partition)
+    )
+
+    for _, definition := range sourceDefinitions {
+        names[definition.PartitionName.String] = definition
+    }
+
+    if strings.HasPrefix(sourceDefinitions[0].PartitionMethod.String, "RANGE") {
+        // 删除最前面源表没有的分区（如按时间清理历史数据）。
+        first := 0
+
+        for first < len(targetDefinitions) {
+            if _, ok := names[targetDefinitions[first].PartitionName.String]; ok {
+                break
+            }
+
+            first++
+        }
+
+        // 重组不能缩小分区范围，源表的上限变小时删除超出上限的分区。
+        last := len(targetDefinitions)
+        upper := sourceDefinitions[len(sourceDefinitions)-1].PartitionDescription.String
+
+        for last > first && comparePartitionBound(targetDefinitions[last-1].PartitionDescription.String, upper) > 0 {
+            last--
+        }
+
+        dropped = append(dropped, targetDefinitions[:first]...)
+        dropped = append(dropped, targetDefinitions[last:]...)
+        remaining = targetDefinitions[first:last]
+    } else {
+        // 源表没有的值不能重组到其他分区，删除包含这些值的分区。
+        values := make(map[string]bool)
+
+        for _, definition := range sourceDefinitions {
+            for _, value := range getPartitionValues(definition.PartitionDescription.String) {
+                values[value] = true
+            }
+        }
+
+        for _, definition := range targetDefinitions {
+            if lo.EveryBy(getPartitionValues(definition.PartitionDescription.String), func(value string) bool {
+                return values[value]
+            }) {
+                remaining = append(remaining, definition)
+            } else {
+                dropped = append(dropped, definition)
+            }
+        }
+    }
+
+    // 不能删除全部分区。
+    if len(remaining) <= 0 {
+        return []AlterSpec{PartitioningChanged{From: targetPartitions, To: sourcePartitions}}
+    }
+
+    if len(dropped) > 0 {
+        specs = append(specs, PartitionDropped{Partitions: dropped})
+    }
+
+    // 找到第一个不同的分区：之前的分区保持不变，之后的分区重组，目标表的分区都不变时在末尾新增。
+    k := 0
+
+    for k < len(remaining) && k < len(sourceDefinitions) && comparePartition(sourceDefinitions[k], remaining[k]) {
+        k++
+    }
+
+    // LIST 分区没有顺序，已有分区都不变时直接新增。
+    if strings.HasPrefix(sourceDefinitions[0].PartitionMethod.String, "LIST") && lo.EveryBy(remaining, func(definition Partition) bool {
+        return comparePartition(names[definition.PartitionName.String], definition)
+    }) {
+        added := lo.Filter(sourceDefinitions, func(definition Partition, _ int) bool {
+            return !lo.ContainsBy(remaining, func(targetDefinition Partition) bool {
+                return targetDefinition.PartitionName.String == definition.PartitionName.String
+            })
+        })
+
+        if len(added) > 0 {
+            specs = append(specs, PartitionAdded{Partitions: added})
+        }
+
+        return specs
+    }
+
+    if k < len(remaining) && k < len(sourceDefinitions) {
+        // 末尾相同的分区也保持不变，但重组前后至少各有一个分区；RANGE 分区只有最后的分区可以扩大上限，其余重组前后的上限必须相同。
+        j := 0
+
+        for j < len(remaining)-k && j < len(sourceDefinitions)-k && comparePartition(sourceDefinitions[len(sourceDefinitions)-1-j], remaining[len(remaining)-1-j]) {
+            j++
+        }
+
+        for j > 0 && (len(remaining)-j <= k || len(sourceDefinitions)-j <= k || strings.HasPrefix(sourceDefinitions[0].PartitionMethod.String, "RANGE") &&
+            remaining[len(remaining)-j-1].PartitionDescription.String != sourceDefinitions[len(sourceDefinitions)-j-1].PartitionDescription.String) {
+            j--
+        }
+
+        specs = append(specs, PartitionReorganized{From: remaining[k : len(remaining)-j], To: sourceDefinitions[k : len(sourceDefinitions)-j]})
+    } else if k < len(sourceDefinitions) {
+        specs = append(specs, PartitionAdded{Partitions: sourceDefinitions[k:]})
+    }
+
+    return specs
+}
+
+// CREATE OR REPLACE VIEW ...
+func (d *differ) createView(sourceTable Table) {
+    sourceView, _ := d.source.View(sourceTable.TableName)
+
+    if _, ok := d.target.Table(sourceTable.TableName); ok {
+        // CREATE OR REPLACE ...
+        targetView, _ := d.target.View(sourceTable.TableName)
+
+        if sourceView.ViewDefinition != targetView.ViewDefinition {
+            d.addChange(&ViewChange{Type: ChangeReplace, Name: sourceTable.TableName, From: &targetView, To: &sourceView})
+        }
+    } else {
+        // CREATE ...
+        d.addChange(&ViewChange{Type: ChangeCreate, Name: sourceTable.TableName, To: &sourceView})
+    }
+}
+
+// DROP TRIGGER ... CREATE TRIGGER ...
+func (d *differ) diffTriggers() {
+    for _, targetTrigger := range d.target.Triggers {
+        if _, ok := d.source.Trigger(targetTrigger.TriggerName); !ok {
+            targetTrigger := targetTrigger
+
+            d.addChange(&TriggerChange{Type: ChangeDrop, Name: targetTrigger.TriggerName, From: &targetTrigger})
+        }
+    }
+
+    // 同一表、时机、事件的触发器按 ACTION_ORDER 排列。
+    groups := make(map[string][]Trigger)
+
+    for _, sourceTrigger := range d.source.Triggers {
+        key := getTriggerGroup(sourceTrigger)
+        groups[key] = append(groups[key], sourceTrigger)
+    }
+
+    for _, triggers := range groups {
+        sort.SliceStable(triggers, func(i, j int) bool {
+            return triggers[i].ActionOrder < triggers[j].ActionOrder
+        })
+    }
+
+    unchanged := d.unchangedTriggers(groups)
+
+    // 按 ACTION_ORDER 依次新建，FOLLOWS 前一个触发器；排在第一位时 PRECEDES 第一个无需重建的触发器。
+    for _, sourceTrigger := range d.source.Triggers {
+        if unchanged[sourceTrigger.TriggerName] {
+            continue
+        }
+
+        sourceTrigger := sourceTrigger
+        change := &TriggerChange{Type: ChangeCreate, Name: sourceTrigger.TriggerName, To: &sourceTrigger}
+
+        if targetTrigger, ok := d.target.Trigger(sourceTrigger.TriggerName); ok {
+            change.Type = ChangeReplace
+            change.From = &targetTrigger
+        }
+
+        triggers := groups[getTriggerGroup(sourceTrigger)]
+
+        for k, trigger := range triggers {
+            if trigger.TriggerName != sourceTrigger.TriggerName {
+                continue
+            }
+
+            if k > 0 {
+                change.Follows = triggers[k-1].TriggerName
+            } else if next, ok := lo.Find(triggers, func(trigger Trigger) bool {
+                return unchanged[trigger.TriggerName]
+            }); ok {
+                change.Precedes = next.TriggerName
+            }
+        }
+
+        d.addChange(change)
+    }
+}
+
+// unchangedTriggers 返回无需重建的触发器：定义相同，且同组中定义相同的触发器按 ACTION_ORDER 的相对顺序不变。
+//
+// 新建或删除其他触发器时 ACTION_ORDER 会变化，因此只比对相对顺序；顺序不同时，从第一个位置不同的触发器起全部重建。
+func (d *differ) unchangedTriggers(groups map[string][]Trigger) map[string]bool {
+    unchanged := make(map[string]bool)
+
+    for _, triggers := range groups {
+        var kept []Trigger
+
+        for _, sourceTrigger := range triggers {
+            if targetTrigger, ok := d.target.Trigger(sourceTrigger.TriggerName); ok && compareTrigger(sourceTrigger, targetTrigger) {
+                kept = append(kept, targetTrigger)
+            }
+        }
+
+        ordered := slices.Clone(kept)
+
+        sort.SliceStable(ordered, func(i, j int) bool {
+            return ordered[i].ActionOrder < ordered[j].ActionOrder
+        })
+
+        for i := range kept {
+            if kept[i].TriggerName != ordered[i].TriggerName {
+                break
+            }
+
+            unchanged[kept[i].TriggerName] = true
+        }
+    }
+
+    return unchanged
+}
+
+// DROP PROCEDURE ... CREATE PROCEDURE ... ALTER PROCEDURE ...
+func (d *differ) diffRoutines() {
+    for _, targetRoutine := range d.target.Routines {
+        if _, ok := d.source.Routine(targetRoutine.RoutineType, targetRoutine.RoutineName); !ok {
+            from, _ := d.target.Routine(targetRoutine.RoutineType, targetRoutine.RoutineName)
+
+            d.addChange(&RoutineChange{Type: ChangeDrop, RoutineType: targetRoutine.RoutineType, Name: targetRoutine.RoutineName, From: &from})
+        }
+    }
+
+    for _, sourceRoutine := range d.source.Routines {
+        to, _ := d.source.Routine(sourceRoutine.RoutineType, sourceRoutine.RoutineName)
+        change := &RoutineChange{Type: ChangeCreate, RoutineType: sourceRoutine.RoutineType, Name: sourceRoutine.RoutineName, To: &to}
+
+        if from, ok := d.target.Routine(sourceRoutine.RoutineType, sourceRoutine.RoutineName); ok {
+            switch {
+            case !compareRoutine(to, from):
+                change.Type = ChangeReplace
+            case !compareRoutineCharacteristics(to.Routine, from.Routine, d.options.Comment):
+                change.Type = ChangeAlter
+            default:
+                continue
+            }
+
+            change.From = &from
+        }
+
+        d.addChange(change)
+    }
+}
+
+// DROP EVENT ... CREATE EVENT ... ALTER EVENT ...
+func (d *differ) diffEvents() {
+    for _, targetEvent := range d.target.Events {
+        if _, ok := d.source.Event(targetEvent.EventName); !ok {
+            targetEvent := targetEvent
+
+            d.addChange(&EventChange{Type: ChangeDrop, Name: targetEvent.EventName, From: &targetEvent})
+        }
+    }
+
+    for _, sourceEvent := range d.source.Events {
+        sourceEvent := sourceEvent
+
+        if targetEvent, ok := d.target.Event(sourceEvent.EventName); ok {
+            if !compareEvent(sourceEvent, targetEvent, d.options.Comment) {
+                d.addChange(&EventChange{Type: ChangeAlter, Name: sourceEvent.EventName, From: &targetEvent, To: &sourceEvent})
+            }
+        } else {
+            d.addChange(&EventChange{Type: ChangeCreate, Name: sourceEvent.EventName, To: &sourceEvent})
+        }
+    }
+}
+
+// CREATE DATABASE ... ALTER DATABASE ...
+//
+// 在其他差异之后比对：目标库只读时先取消只读，修改完成后按源库恢复只读。
+func (d *differ) diffDatabase() {
+    // 回滚时不删除新建的库。
+    if d.source.missing {
+        return
+    }
+
+    var (
+        sourceSchema = d.source.Schema
+        targetSchema = d.target.Schema
+        name         = targetSchema.SchemaName
+        changed      = len(d.result.Changes) > 0
+    )
+
+    if d.target.missing {
+        d.addChange(&DatabaseChange{Type: ChangeCreate, Name: name, To: &sourceSchema})
+    } else if d.options.Database {
+        same := compareSchema(sourceSchema, targetSchema)
+
+        if targetSchema.ReadOnly && (changed || !same || !sourceSchema.ReadOnly) {
+            d.addChange(&DatabaseChange{Type: ChangeAlter, Name: name, From: &targetSchema, To: &sourceSchema, ReadOnly: lo.ToPtr(false)})
+        }
+
+        if !same {
+            d.addChange(&DatabaseChange{Type: ChangeAlter, Name: name, From: &targetSchema, To: &sourceSchema})
+        }
+    }
+
+    if d.options.Database && sourceSchema.ReadOnly && (len(d.result.Changes) > 0 || !targetSchema.ReadOnly) {
+        d.addChange(&DatabaseChange{Type: ChangeAlter, Name: name, From: &targetSchema, To: &sourceSchema, ReadOnly: lo.ToPtr(true)})
+    }
+}
@@ -0,0 +1,187 @@
+package mysqldiff
+
+import (
+    "slices"
+    "strings"
+    "testing"
+)
+
+func TestAlterPartitions(t *testing.T) {
+    const (
+        rangeTable = "CREATE TABLE t (id int NOT NULL, created date NOT NULL) PARTITION BY RANGE (TO_DAYS(created)) (%s);"
+        listTable  = "CREATE TABLE t (id int NOT NULL, region int NOT NULL) PARTITION BY LIST (region) (%s);"
+    )
+
+    var (
+        p202401 = "PARTITION p202401 VALUES LESS THAN (TO_DAYS('2024-02-01'))"
+        p202402 = "PARTITION p202402 VALUES LESS THAN (TO_DAYS('2024-03-01'))"
+        p202403 = "PARTITION p202403 VALUES LESS THAN (TO_DAYS('2024-04-01'))"
+        pmax    = "PARTITION pmax VALUES LESS THAN MAXVALUE"
+    )
+
+    tests := []struct {
+        name   string
+        table  string
+        source []string
+        target []string
+        kinds  []string
+        script []string
+        down   []string
+    }{
+        {
+            name:   "删除最前面的分区",
+            table:  rangeTable,
+            source: []string{p202402, p202403, pmax},
+            target: []string{p202401, p202402, p202403, pmax},
+            kinds:  []string{"PARTITION_DROPPED"},
+            script: []string{"DROP PARTITION `p202401`"},
+        },
+        {
+            name:   "拆分最后的分区",
+            table:  rangeTable,
+            source: []string{p202401, p202402, pmax},
+            target: []string{p202401, pmax},
+            kinds:  []string{"PARTITION_REORGANIZED"},
+            script: []string{"REORGANIZE PARTITION `pmax` INTO (\nPARTITION `p202402`"},
+        },
+        {
+            name:   "回滚拆分",
+            table:  rangeTable,
+            source: []string{p202401, pmax},
+            target: []string{p202401, p202402, pmax},
+            kinds:  []string{"PARTITION_REORGANIZED"},
+            script: []string{"REORGANIZE PARTITION `p202402`, `pmax` INTO (\nPARTITION `pmax`"},
+        },
+        {
+            name:   "删除中间的分区",
+            table:  rangeTable,
+            source: []string{p202401, p202403, pmax},
+            target: []string{p202401, p202402, p202403, pmax},
+            kinds:  []string{"PARTITION_REORGANIZED"},
+            script: []string{"REORGANIZE PARTITION `p202402`, `p202403` INTO (\nPARTITION `p202403`"},
+        },
+        {
+            name:   "删除最后的分区",
+            table:  rangeTable,
+            source: []string{p202401, p202402},
+            target: []string{p202401, p202402, pmax},
+            kinds:  []string{"PARTITION_DROPPED"},
+            script: []string{"ALTER TABLE `t` DROP PARTITION `pmax`;"},
+            down:   []string{"ALTER TABLE `t` ADD PARTITION (\nPARTITION `pmax` VALUES LESS THAN MAXVALUE\n);"},
+        },
+        {
+            name:   "缩小最后的分区",
+            table:  rangeTable,
+            source: []string{p202401, p202402},
+            target: []string{p202401, pmax},
+            kinds:  []string{"PARTITION_DROPPED", "PARTITION_ADDED"},
+            script: []string{"ALTER TABLE `t` DROP PARTITION `pmax`;", "ALTER TABLE `t` ADD PARTITION (\nPARTITION `p202402` VALUES LESS THAN (739311)\n);"},
+            down:   []string{"ALTER TABLE `t` REORGANIZE PARTITION `p202402` INTO (\nPARTITION `pmax` VALUES LESS THAN MAXVALUE\n);"},
+        },
+        {
+            name:   "删除最前面并新增最后的分区",
+            table:  rangeTable,
+            source: []string{p202402, p202403},
+            target: []string{p202401, p202402},
+            kinds:  []string{"PARTITION_DROPPED", "PARTITION_ADDED"},
+            script: []string{"ALTER TABLE `t` DROP PARTITION `p202401`;", "ALTER TABLE `t` ADD PARTITION (\nPARTITION `p202403` VALUES LESS THAN (739342)\n);"},
+            down:   []string{"ALTER TABLE `t` DROP PARTITION `p202403`;", "ALTER TABLE `t` REORGANIZE PARTITION `p202402` INTO (\nPARTITION `p202401` VALUES LESS THAN (739282),\nPARTITION `p202402` VALUES LESS THAN (739311)\n);"},
+        },
+        {
+            name:   "LIST 新增分区",
+            table:  listTable,
+            source: []string{"PARTITION pa VALUES IN (1,2)", "PARTITION pb VALUES IN (3)"},
+            target: []string{"PARTITION pa VALUES IN (1,2)"},
+            kinds:  []string{"PARTITION_ADDED"},
+            script: []string{"ADD PARTITION (\nPARTITION `pb`"},
+        },
+        {
+            name:   "LIST 合并分区",
+            table:  listTable,
+            source: []string{"PARTITION pa VALUES IN (1,2,3)"},
+            target: []string{"PARTITION pa VALUES IN (1,2)", "PARTITION pb VALUES IN (3)"},
+            kinds:  []string{"PARTITION_REORGANIZED"},
+            script: []string{"REORGANIZE PARTITION `pa`, `pb` INTO (\nPARTITION `pa`"},
+        },
+        {
+            name:   "LIST 删除分区",
+            table:  listTable,
+            source: []string{"PARTITION pa VALUES IN (1,2)"},
+            target: []string{"PARTITION pa VALUES IN (1,2)", "PARTITION pb VALUES IN (3)"},
+            kinds:  []string{"PARTITION_DROPPED"},
+            script: []string{"ALTER TABLE `t` DROP PARTITION `pb`;"},
+            down:   []string{"ALTER TABLE `t` ADD PARTITION (\nPARTITION `pb` VALUES IN (3)\n);"},
+        },
+        {
+            name:   "LIST 删除值",
+            table:  listTable,
+            source: []string{"PARTITION pa VALUES IN (1)", "PARTITION pb VALUES IN (3)"},
+            target: []string{"PARTITION pa VALUES IN (1,2)", "PARTITION pb VALUES IN (3)"},
+            kinds:  []string{"PARTITION_DROPPED", "PARTITION_ADDED"},
+            script: []string{"ALTER TABLE `t` DROP PARTITION `pa`;", "ALTER TABLE `t` ADD PARTITION (\nPARTITION `pa` VALUES IN (1)\n);"},
+            down:   []string{"ALTER TABLE `t` REORGANIZE PARTITION `pa` INTO (\nPARTITION `pa` VALUES IN (1,2)\n);"},
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            source := mustParseDDL(t, strings.Replace(tt.table, "%s", strings.Join(tt.source, ", "), 1))
+            target := mustParseDDL(t, strings.Replace(tt.table, "%s", strings.Join(tt.target, ", "), 1))
+
+            result := Compare(source, target, Options{})
+
+            if kinds := getSpecKinds(result); !slices.Equal(kinds, tt.kinds) {
+                t.Fatalf("specs = %v, want %v", kinds, tt.kinds)
+            }
+
+            assertScript(t, result, tt.script...)
+
+            if script := result.Script(); strings.Contains(script, "DROP PARTITION") && tt.kinds[0] != "PARTITION_DROPPED" {
+                t.Errorf("script drops partitions:\n%s", script)
+            }
+
+            if tt.down != nil {
+                assertScript(t, result.Down(), tt.down...)
+            }
+        })
+    }
+}
+
+func TestComparePartitioning(t *testing.T) {
+    tests := []struct {
+        name   string
+        source string
+        target string
+        kinds  []string
+        script string
+    }{
+        {"相同", "PARTITION BY HASH (id) PARTITIONS 4", "PARTITION BY HASH (`id`) PARTITIONS 4", nil, ""},
+        {"增加分区", "PARTITION BY HASH (id) PARTITIONS 4", "", []string{"PARTITIONING_CHANGED"}, "ALTER TABLE `t`\nPARTITION BY HASH (`id`)\nPARTITIONS 4;"},
+        {"修改分区方式", "PARTITION BY KEY (id) PARTITIONS 4", "PARTITION BY HASH (id) PARTITIONS 4", []string{"PARTITIONING_CHANGED"}, "PARTITION BY KEY (`id`)\nPARTITIONS 4;"},
+        {"修改分区数", "PARTITION BY HASH (id) PARTITIONS 8", "PARTITION BY HASH (id) PARTITIONS 4", []string{"PARTITIONING_CHANGED"}, "PARTITION BY HASH (`id`)\nPARTITIONS 8;"},
+        {"去掉分区", "", "PARTITION BY HASH (id) PARTITIONS 4", []string{"PARTITIONING_REMOVED"}, "ALTER TABLE `t` REMOVE PARTITIONING;"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            source := mustParseDDL(t, "CREATE TABLE t (id int NOT NULL) "+tt.source+";")
+            target := mustParseDDL(t, "CREATE TABLE t (id int NOT NULL) "+tt.target+";")
+
+            result := Compare(source, target, Options{})
+
+            if kinds := getSpecKinds(result); !slices.Equal(kinds, tt.kinds) {
+                t.Fatalf("specs = %v, want %v", kinds, tt.kinds)
+            }
+
+            assertScript(t, result, tt.script)
+        })
+    }
+}
+
+func TestCreatePartitionedTable(t *testing.T) {
+    source := mustParseDDL(t, "CREATE TABLE t (id int NOT NULL, created date NOT NULL) PARTITION BY RANGE (TO_DAYS(created)) (PARTITION p202401 VALUES LESS THAN (TO_DAYS('2024-02-01')), PARTITION pmax VALUES LESS THAN MAXVALUE);")
+    target := mustParseDDL(t, "CREATE TABLE a (id int);")
+
+    // 分区值为表达式的计算结果，与 information_schema 一致。
+    assertScript(t, Compare(source, target, Options{}), "COLLATE=utf8mb4_0900_ai_ci\nPARTITION BY RANGE (to_days(`created`))\n(PARTITION `p202401` VALUES LESS THAN (739282),\n PARTITION `pmax` VALUES LESS THAN MAXVALUE);")
+}
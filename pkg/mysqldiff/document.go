@@ -0,0 +1,487 @@
+package mysqldiff
+
+import (
+    "fmt"
+    "sort"
+)
+
+// DocumentVersion 差异文档格式版本，字段含义变化或删除字段时递增，新增字段不递增。
+const DocumentVersion = 1
+
+// Document 可供程序读取的差异文档，格式说明见 README。
+type Document struct {
+    Version int              `json:"version" yaml:"version"`
+    Schema  string           `json:"schema" yaml:"schema"`
+    Changes []DocumentChange `json:"changes" yaml:"changes"`
+}
+
+// DocumentChange 单个对象的差异，Old 为目标对象，New 为源对象，重命名时 OldName 为旧名。
+type DocumentChange struct {
+    ObjectType string         `json:"objectType" yaml:"objectType"`
+    Name       string         `json:"name" yaml:"name"`
+    OldName    string         `json:"oldName,omitempty" yaml:"oldName,omitempty"`
+    Type       ChangeType     `json:"type" yaml:"type"`
+    Old        interface{}    `json:"old,omitempty" yaml:"old,omitempty"`
+    New        interface{}    `json:"new,omitempty" yaml:"new,omitempty"`
+    Specs      []DocumentSpec `json:"specs,omitempty" yaml:"specs,omitempty"`
+    Sql        []string       `json:"sql" yaml:"sql"`
+}
+
+// DocumentSpec 表中单项差异，Kind 与 AlterSpec.Kind() 一致。
+// 分区的 Old、New 为 PARTITION BY 子句或分区定义列表。
+// 重命名时 Name 为新名，OldName 为旧名。
+type DocumentSpec struct {
+    Kind    string      `json:"kind" yaml:"kind"`
+    Name    string      `json:"name" yaml:"name"`
+    OldName string      `json:"oldName,omitempty" yaml:"oldName,omitempty"`
+    Old     interface{} `json:"old,omitempty" yaml:"old,omitempty"`
+    New     interface{} `json:"new,omitempty" yaml:"new,omitempty"`
+}
+
+// TableAttributes 表属性，Options 为指定了非默认值的扩展表选项。
+type TableAttributes struct {
+    Engine    string            `json:"engine" yaml:"engine"`
+    Collation string            `json:"collation" yaml:"collation"`
+    Comment   string            `json:"comment" yaml:"comment"`
+    Options   map[string]string `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// ColumnAttributes 列属性，Default 为 null 时表示没有默认值，After 为空字符串时表示 FIRST，Srid 仅空间列有值，Generated 为生成列的表达式。
+type ColumnAttributes struct {
+    Type      string  `json:"type" yaml:"type"`
+    Srid      *int64  `json:"srid,omitempty" yaml:"srid,omitempty"`
+    Nullable  bool    `json:"nullable" yaml:"nullable"`
+    Default   *string `json:"default" yaml:"default"`
+    Charset   string  `json:"charset,omitempty" yaml:"charset,omitempty"`
+    Collation string  `json:"collation,omitempty" yaml:"collation,omitempty"`
+    Extra     string  `json:"extra,omitempty" yaml:"extra,omitempty"`
+    Generated string  `json:"generated,omitempty" yaml:"generated,omitempty"`
+    Comment   string  `json:"comment,omitempty" yaml:"comment,omitempty"`
+    After     *string `json:"after,omitempty" yaml:"after,omitempty"`
+}
+
+// IndexAttributes 索引属性，Type 为 BTREE、HASH、FULLTEXT 或 SPATIAL，Columns 中前缀索引写作 name(length)，函数索引写作 (expression)，降序时加 DESC，Parser 为全文索引的解析器，Invisible 为不可见索引。
+type IndexAttributes struct {
+    Unique    bool     `json:"unique" yaml:"unique"`
+    Type      string   `json:"type" yaml:"type"`
+    Columns   []string `json:"columns" yaml:"columns"`
+    Parser    string   `json:"parser,omitempty" yaml:"parser,omitempty"`
+    Comment   string   `json:"comment,omitempty" yaml:"comment,omitempty"`
+    Invisible bool     `json:"invisible,omitempty" yaml:"invisible,omitempty"`
+}
+
+type ForeignKeyAttributes struct {
+    Columns           []string `json:"columns" yaml:"columns"`
+    ReferencedTable   string   `json:"referencedTable" yaml:"referencedTable"`
+    ReferencedColumns []string `json:"referencedColumns" yaml:"referencedColumns"`
+    OnDelete          string   `json:"onDelete" yaml:"onDelete"`
+    OnUpdate          string   `json:"onUpdate" yaml:"onUpdate"`
+}
+
+// CheckAttributes CHECK 约束属性，Clause 含最外层的括号。
+type CheckAttributes struct {
+    Clause   string `json:"clause" yaml:"clause"`
+    Enforced bool   `json:"enforced" yaml:"enforced"`
+}
+
+type TriggerAttributes struct {
+    Table   string `json:"table" yaml:"table"`
+    Timing  string `json:"timing" yaml:"timing"`
+    Event   string `json:"event" yaml:"event"`
+    Order   int64  `json:"order" yaml:"order"`
+    Definer string `json:"definer,omitempty" yaml:"definer,omitempty"`
+    Body    string `json:"body" yaml:"body"`
+}
+
+// RoutineAttributes 存储过程或函数的属性，Returns 仅函数有值。
+type RoutineAttributes struct {
+    Parameters    string `json:"parameters" yaml:"parameters"`
+    Returns       string `json:"returns,omitempty" yaml:"returns,omitempty"`
+    Deterministic bool   `json:"deterministic" yaml:"deterministic"`
+    DataAccess    string `json:"dataAccess" yaml:"dataAccess"`
+    Security      string `json:"security" yaml:"security"`
+    Comment       string `json:"comment,omitempty" yaml:"comment,omitempty"`
+    Definer       string `json:"definer,omitempty" yaml:"definer,omitempty"`
+    Body          string `json:"body" yaml:"body"`
+}
+
+// EventAttributes 事件属性，Schedule 为 ON SCHEDULE 之后的部分。
+type EventAttributes struct {
+    Schedule     string `json:"schedule" yaml:"schedule"`
+    Status       string `json:"status" yaml:"status"`
+    OnCompletion string `json:"onCompletion" yaml:"onCompletion"`
+    Comment      string `json:"comment,omitempty" yaml:"comment,omitempty"`
+    Definer      string `json:"definer,omitempty" yaml:"definer,omitempty"`
+    Body         string `json:"body" yaml:"body"`
+}
+
+// DatabaseAttributes 库属性，Encryption 为 YES 或 NO，服务器不支持时为空。
+type DatabaseAttributes struct {
+    Charset    string `json:"charset" yaml:"charset"`
+    Collation  string `json:"collation" yaml:"collation"`
+    Encryption string `json:"encryption,omitempty" yaml:"encryption,omitempty"`
+    ReadOnly   bool   `json:"readOnly" yaml:"readOnly"`
+}
+
+type ViewAttributes struct {
+    Definition   string `json:"definition" yaml:"definition"`
+    SecurityType string `json:"securityType" yaml:"securityType"`
+}
+
+// Document 返回差异文档。
+func (r *Result) Document() Document {
+    document := Document{
+        Version: DocumentVersion,
+        Schema:  r.Schema.SchemaName,
+        Changes: []DocumentChange{},
+    }
+
+    renderer := r.Renderer()
+
+    for _, change := range r.Changes {
+        documentChange := getDocumentChange(change)
+        documentChange.Sql = renderer.Render(change)
+
+        document.Changes = append(document.Changes, documentChange)
+    }
+
+    return document
+}
+
+func getDocumentChange(change Change) DocumentChange {
+    switch c := change.(type) {
+    case *DatabaseChange:
+        documentChange := DocumentChange{ObjectType: "DATABASE", Name: c.Name, Type: c.Type}
+
+        if c.From != nil {
+            documentChange.Old = getDatabaseAttributes(*c.From)
+        }
+
+        if c.To != nil {
+            documentChange.New = getDatabaseAttributes(*c.To)
+        }
+
+        return documentChange
+    case *TableChange:
+        documentChange := DocumentChange{ObjectType: "TABLE", Name: c.Table.TableName, Type: c.Type}
+
+        switch c.Type {
+        case ChangeCreate:
+            documentChange.New = getTableAttributes(c.Table)
+
+            for k, column := range c.Columns {
+                after := ""
+
+                if k > 0 {
+                    after = c.Columns[k-1].ColumnName
+                }
+
+                documentChange.Specs = append(documentChange.Specs, getDocumentSpec(ColumnAdded{Column: column, After: after}))
+            }
+
+            for _, index := range c.Indexes {
+                documentChange.Specs = append(documentChange.Specs, getDocumentSpec(IndexAdded{Index: index}))
+            }
+
+            for _, foreignKey := range c.ForeignKeys {
+                documentChange.Specs = append(documentChange.Specs, getDocumentSpec(ForeignKeyAdded{ForeignKey: foreignKey}))
+            }
+
+            for _, check := range c.Checks {
+                documentChange.Specs = append(documentChange.Specs, getDocumentSpec(CheckAdded{Check: check}))
+            }
+
+            if len(c.Partitions) > 0 {
+                documentChange.Specs = append(documentChange.Specs, getDocumentSpec(PartitioningChanged{To: c.Partitions}))
+            }
+        case ChangeAlter, ChangeRename:
+            documentChange.OldName = c.OldName
+
+            for _, spec := range c.Specs {
+                documentChange.Specs = append(documentChange.Specs, getDocumentSpec(spec))
+            }
+        case ChangeDrop:
+            documentChange.Old = getTableAttributes(c.Table)
+        }
+
+        return documentChange
+    case *ViewChange:
+        documentChange := DocumentChange{ObjectType: "VIEW", Name: c.Name, Type: c.Type}
+
+        if c.From != nil {
+            documentChange.Old = getViewAttributes(*c.From)
+        }
+
+        if c.To != nil {
+            documentChange.New = getViewAttributes(*c.To)
+        }
+
+        return documentChange
+    case *TriggerChange:
+        documentChange := DocumentChange{ObjectType: "TRIGGER", Name: c.Name, Type: c.Type}
+
+        if c.From != nil {
+            documentChange.Old = getTriggerAttributes(*c.From)
+        }
+
+        if c.To != nil {
+            documentChange.New = getTriggerAttributes(*c.To)
+        }
+
+        return documentChange
+    case *RoutineChange:
+        documentChange := DocumentChange{ObjectType: c.RoutineType, Name: c.Name, Type: c.Type}
+
+        if c.From != nil {
+            documentChange.Old = getRoutineAttributes(*c.From)
+        }
+
+        if c.To != nil {
+            documentChange.New = getRoutineAttributes(*c.To)
+        }
+
+        return documentChange
+    case *EventChange:
+        documentChange := DocumentChange{ObjectType: "EVENT", Name: c.Name, Type: c.Type}
+
+        if c.From != nil {
+            documentChange.Old = getEventAttributes(*c.From)
+        }
+
+        if c.To != nil {
+            documentChange.New = getEventAttributes(*c.To)
+        }
+
+        return documentChange
+    }
+
+    return DocumentChange{Name: change.ObjectName()}
+}
+
+func getDocumentSpec(spec AlterSpec) DocumentSpec {
+    documentSpec := DocumentSpec{Kind: spec.Kind()}
+
+    switch s := spec.(type) {
+    case ColumnAdded:
+        documentSpec.Name = s.Column.ColumnName
+        documentSpec.New = getColumnAttributes(s.Column, &s.After)
+    case ColumnDropped:
+        documentSpec.Name = s.Column.ColumnName
+        documentSpec.Old = getColumnAttributes(s.Column, nil)
+    case ColumnModified:
+        documentSpec.Name = s.To.ColumnName
+        documentSpec.Old = getColumnAttributes(s.From, nil)
+        documentSpec.New = getColumnAttributes(s.To, &s.After)
+    case ColumnRenamed:
+        documentSpec.Name = s.To.ColumnName
+        documentSpec.OldName = s.From.ColumnName
+        documentSpec.Old = getColumnAttributes(s.From, nil)
+        documentSpec.New = getColumnAttributes(s.To, &s.After)
+    case IndexAdded:
+        documentSpec.Name = s.Index.Name
+        documentSpec.New = getIndexAttributes(s.Index)
+    case IndexDropped:
+        documentSpec.Name = s.Index.Name
+        documentSpec.Old = getIndexAttributes(s.Index)
+    case IndexModified:
+        documentSpec.Name = s.To.Name
+        documentSpec.Old = getIndexAttributes(s.From)
+        documentSpec.New = getIndexAttributes(s.To)
+    case ForeignKeyAdded:
+        documentSpec.Name = s.ForeignKey.Constraint.ConstraintName
+        documentSpec.New = getForeignKeyAttributes(s.ForeignKey)
+    case ForeignKeyDropped:
+        documentSpec.Name = s.ForeignKey.Constraint.ConstraintName
+        documentSpec.Old = getForeignKeyAttributes(s.ForeignKey)
+    case ForeignKeyModified:
+        documentSpec.Name = s.To.Constraint.ConstraintName
+        documentSpec.Old = getForeignKeyAttributes(s.From)
+        documentSpec.New = getForeignKeyAttributes(s.To)
+    case CheckAdded:
+        documentSpec.Name = s.Check.Constraint.ConstraintName
+        documentSpec.New = getCheckAttributes(s.Check)
+    case CheckDropped:
+        documentSpec.Name = s.Check.Constraint.ConstraintName
+        documentSpec.Old = getCheckAttributes(s.Check)
+    case CheckModified:
+        documentSpec.Name = s.To.Constraint.ConstraintName
+        documentSpec.Old = getCheckAttributes(s.From)
+        documentSpec.New = getCheckAttributes(s.To)
+    case TableOptionChanged:
+        documentSpec.Name = s.Name
+        documentSpec.Old = s.From
+        documentSpec.New = s.To
+    case PartitioningChanged:
+        documentSpec.Name = "PARTITION BY"
+
+        if len(s.From) > 0 {
+            documentSpec.Old = getPartitionBy(s.From)
+        }
+
+        documentSpec.New = getPartitionBy(s.To)
+    case PartitioningRemoved:
+        documentSpec.Name = "PARTITION BY"
+        documentSpec.Old = getPartitionBy(s.From)
+    case PartitionAdded:
+        documentSpec.Name = getPartitionNames(s.Partitions)
+        documentSpec.New = getPartitionDefinitionSql(s.Partitions)
+    case PartitionDropped:
+        documentSpec.Name = getPartitionNames(s.Partitions)
+        documentSpec.Old = getPartitionDefinitionSql(s.Partitions)
+    case PartitionReorganized:
+        documentSpec.Name = getPartitionNames(s.From)
+        documentSpec.Old = getPartitionDefinitionSql(s.From)
+        documentSpec.New = getPartitionDefinitionSql(s.To)
+    }
+
+    return documentSpec
+}
+
+func getDatabaseAttributes(schema Schema) DatabaseAttributes {
+    return DatabaseAttributes{
+        Charset:    schema.DefaultCharacterSetName,
+        Collation:  schema.DefaultCollationName,
+        Encryption: schema.DefaultEncryption.String,
+        ReadOnly:   schema.ReadOnly,
+    }
+}
+
+func getTableAttributes(table Table) TableAttributes {
+    return TableAttributes{
+        Engine:    table.ENGINE.String,
+        Collation: table.TableCollation.String,
+        Comment:   table.TableComment,
+        Options:   getTableOptions(table),
+    }
+}
+
+func getColumnAttributes(column Column, after *string) ColumnAttributes {
+    attributes := ColumnAttributes{
+        Type:      column.ColumnType,
+        Nullable:  column.IsNullable == "YES",
+        Charset:   column.CharacterSetName.String,
+        Collation: column.CollationName.String,
+        Extra:     column.EXTRA,
+        Generated: column.GenerationExpression,
+        Comment:   column.ColumnComment,
+        After:     after,
+    }
+
+    if column.ColumnDefault.Valid {
+        attributes.Default = &column.ColumnDefault.String
+    }
+
+    if column.SrsId.Valid {
+        attributes.Srid = &column.SrsId.Int64
+    }
+
+    return attributes
+}
+
+func getIndexAttributes(index Index) IndexAttributes {
+    var seqInIndexSort []int
+
+    for seqInIndex := range index.Statistics {
+        seqInIndexSort = append(seqInIndexSort, seqInIndex)
+    }
+
+    sort.Ints(seqInIndexSort)
+
+    attributes := IndexAttributes{
+        Unique:    index.Statistics[seqInIndexSort[0]].NonUnique == 0,
+        Type:      index.Statistics[seqInIndexSort[0]].IndexType,
+        Parser:    index.Statistics[seqInIndexSort[0]].Parser,
+        Comment:   index.Statistics[seqInIndexSort[0]].IndexComment,
+        Invisible: !isVisibleIndex(index.Statistics[seqInIndexSort[0]]),
+    }
+
+    for _, seqInIndex := range seqInIndexSort {
+        statistic := index.Statistics[seqInIndex]
+
+        column := statistic.ColumnName
+
+        switch {
+        case statistic.EXPRESSION.Valid:
+            column = fmt.Sprintf("(%s)", statistic.EXPRESSION.String)
+        case statistic.SubPart.Valid && statistic.IndexType != "SPATIAL":
+            column = fmt.Sprintf("%s(%d)", statistic.ColumnName, statistic.SubPart.Int32)
+        }
+
+        if isDescending(statistic) {
+            column += " DESC"
+        }
+
+        attributes.Columns = append(attributes.Columns, column)
+    }
+
+    return attributes
+}
+
+func getCheckAttributes(check Check) CheckAttributes {
+    return CheckAttributes{
+        Clause:   check.CheckClause,
+        Enforced: isEnforced(check),
+    }
+}
+
+func getForeignKeyAttributes(foreignKey ForeignKey) ForeignKeyAttributes {
+    attributes := ForeignKeyAttributes{
+        ReferencedTable: foreignKey.Referential.ReferencedTableName,
+        OnDelete:        foreignKey.Referential.DeleteRule,
+        OnUpdate:        foreignKey.Referential.UpdateRule,
+    }
+
+    for _, keyColumnUsage := range foreignKey.KeyColumnUsages {
+        attributes.Columns = append(attributes.Columns, keyColumnUsage.ColumnName)
+        attributes.ReferencedColumns = append(attributes.ReferencedColumns, keyColumnUsage.ReferencedColumnName)
+    }
+
+    return attributes
+}
+
+func getViewAttributes(view View) ViewAttributes {
+    return ViewAttributes{
+        Definition:   view.ViewDefinition,
+        SecurityType: view.SecurityType,
+    }
+}
+
+func getTriggerAttributes(trigger Trigger) TriggerAttributes {
+    return TriggerAttributes{
+        Table:   trigger.EventObjectTable,
+        Timing:  trigger.ActionTiming,
+        Event:   trigger.EventManipulation,
+        Order:   trigger.ActionOrder,
+        Definer: trigger.DEFINER,
+        Body:    trigger.ActionStatement,
+    }
+}
+
+func getRoutineAttributes(routine StoredRoutine) RoutineAttributes {
+    attributes := RoutineAttributes{
+        Parameters:    getRoutineParameters(routine.Parameters),
+        Deterministic: routine.Routine.IsDeterministic == "YES",
+        DataAccess:    routine.Routine.SqlDataAccess,
+        Security:      routine.Routine.SecurityType,
+        Comment:       routine.Routine.RoutineComment,
+        Definer:       routine.Routine.DEFINER,
+        Body:          routine.Routine.RoutineDefinition.String,
+    }
+
+    if routine.Routine.RoutineType == "FUNCTION" {
+        attributes.Returns = routine.Routine.DtdIdentifier.String
+    }
+
+    return attributes
+}
+
+func getEventAttributes(event Event) EventAttributes {
+    return EventAttributes{
+        Schedule:     getEventSchedule(event, true, true),
+        Status:       event.STATUS,
+        OnCompletion: event.OnCompletion,
+        Comment:      event.EventComment,
+        Definer:      event.DEFINER,
+        Body:         event.EventDefinition,
+    }
+}
@@ -0,0 +1,92 @@
+package mysqldiff
+
+import (
+    "encoding/json"
+    "reflect"
+    "strings"
+    "testing"
+
+    "gopkg.in/yaml.v3"
+)
+
+func TestDocument(t *testing.T) {
+    source := mustParseDDL(t, "CREATE TABLE t (id int NOT NULL, n varchar(10) DEFAULT 'x', KEY idx (n));")
+    target := mustParseDDL(t, "CREATE TABLE t (id int NOT NULL, m int);")
+
+    document := Compare(source, target, Options{}).Document()
+
+    if len(document.Changes) != 1 || len(document.Changes[0].Sql) != 1 || !strings.HasPrefix(document.Changes[0].Sql[0], "ALTER TABLE `t`") {
+        t.Fatalf("changes = %+v", document.Changes)
+    }
+
+    // 语句单独检查，其余字段与格式说明一致。
+    document.Changes[0].Sql = nil
+
+    data, err := json.Marshal(document)
+
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    want := `{
+        "version": 1,
+        "schema": "db",
+        "changes": [{
+            "objectType": "TABLE",
+            "name": "t",
+            "type": "ALTER",
+            "specs": [
+                {"kind": "COLUMN_DROPPED", "name": "m", "old": {"type": "int", "nullable": true, "default": null}},
+                {"kind": "COLUMN_ADDED", "name": "n", "new": {"type": "varchar(10)", "nullable": true, "default": "x", "charset": "utf8mb4", "collation": "utf8mb4_0900_ai_ci", "after": "id"}},
+                {"kind": "INDEX_ADDED", "name": "idx", "new": {"unique": false, "type": "BTREE", "columns": ["n"]}}
+            ],
+            "sql": null
+        }]
+    }`
+
+    var got, expected interface{}
+
+    if err := json.Unmarshal(data, &got); err != nil {
+        t.Fatal(err)
+    }
+
+    if err := json.Unmarshal([]byte(want), &expected); err != nil {
+        t.Fatal(err)
+    }
+
+    if !reflect.DeepEqual(got, expected) {
+        t.Fatalf("document = %s", data)
+    }
+}
+
+func TestDocumentYaml(t *testing.T) {
+    source := mustParseDDL(t, "CREATE TABLE t (id int); CREATE TABLE a (id int);")
+    target := mustParseDDL(t, "CREATE TABLE t (id int);")
+
+    data, err := yaml.Marshal(Compare(source, target, Options{}).Document())
+
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    for _, s := range []string{"version: 1\n", "schema: db\n", "objectType: TABLE\n", "type: CREATE\n", "kind: COLUMN_ADDED\n", "sql:\n"} {
+        if !strings.Contains(string(data), s) {
+            t.Errorf("yaml does not contain %q:\n%s", s, data)
+        }
+    }
+}
+
+func TestDocumentEmpty(t *testing.T) {
+    catalog := mustParseDDL(t, "CREATE TABLE t (id int);")
+
+    data, err := json.Marshal(Compare(catalog, catalog, Options{}).Document())
+
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // 无差异时 changes 为空数组而不是 null。
+    if string(data) != `{"version":1,"schema":"db","changes":[]}` {
+        t.Fatalf("document = %s", data)
+    }
+}
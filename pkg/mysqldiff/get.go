@@ -0,0 +1,918 @@
+package mysqldiff
+
+import (
+    "encoding/hex"
+    "fmt"
+    "regexp"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/samber/lo"
+)
+
+var (
+    // charsetIntroducerPattern 字符串的字符集前缀，如 _utf8mb4'$.a'，只匹配字符串之前的部分。
+    charsetIntroducerPattern = regexp.MustCompile("(?i)\\b_\\w+\\s*$")
+
+    // expressionReplacer 不等号的两种写法。
+    expressionReplacer = strings.NewReplacer("<>", "!=")
+
+    // currentTimestampPattern CURRENT_TIMESTAMP 及其同义词，子匹配为精度。
+    currentTimestampPattern = regexp.MustCompile("(?i)^(?:CURRENT_TIMESTAMP|NOW|LOCALTIME|LOCALTIMESTAMP)(?:\\((\\d*)\\))?$")
+
+    // defaultFunctionReplacer MySQL 保存表达式时使用的函数名。
+    defaultFunctionReplacer = strings.NewReplacer("current_date()", "curdate()", "current_time()", "curtime()", "current_timestamp()", "now()", "localtimestamp()", "now()", "localtime()", "now()")
+
+    bitLiteralPattern = regexp.MustCompile("(?i)^b'[01]*'$")
+    hexLiteralPattern = regexp.MustCompile("(?i)^0x[0-9a-f]*$")
+
+    // createOptionPattern CREATE_OPTIONS 中的选项，如 row_format=COMPRESSED、COMPRESSION="zlib"。
+    createOptionPattern = regexp.MustCompile("(\\w+)=(\"[^\"]*\"|'[^']*'|\\S+)")
+
+    // tidbDefaultFunctionPattern TiDB 支持的表达式默认值。
+    tidbDefaultFunctionPattern = regexp.MustCompile("(?i)^(?:rand|uuid|uuid_to_bin|date_format|replace|upper|lower|str_to_date|json_object|json_array|json_quote|nextval|extract|date|current_date|curdate)\\(.*\\)$")
+)
+
+func getColumnNullAbleDefault(column Column, tidb bool) string {
+    var nullAbleDefault = ""
+
+    if column.IsNullable == "NO" {
+        nullAbleDefault = " NOT NULL"
+    } else if lo.Contains([]string{"timestamp", "datetime"}, column.DataType) {
+        nullAbleDefault = " NULL"
+    }
+
+    if column.ColumnDefault.Valid {
+        return fmt.Sprintf("%s DEFAULT %s", nullAbleDefault, getColumnDefault(column, tidb))
+    }
+
+    if column.IsNullable == "NO" {
+        return nullAbleDefault
+    }
+
+    return nullAbleDefault + " DEFAULT NULL"
+}
+
+// getColumnDefault 返回 DEFAULT 之后的部分。
+//
+// TIMESTAMP、DATETIME 列的 CURRENT_TIMESTAMP 不加引号；表达式默认值（EXTRA 含 DEFAULT_GENERATED，TiDB 按函数名判断）写在括号中；
+// BIT 的 b'...' 与二进制列的 0x... 原样输出；其余按字符串转义后加引号。
+func getColumnDefault(column Column, tidb bool) string {
+    value := column.ColumnDefault.String
+
+    switch {
+    case isCurrentTimestampDefault(column):
+        return strings.ToUpper(value)
+    case isDefaultExpression(column, tidb):
+        return fmt.Sprintf("(%s)", getDefaultExpression(value))
+    case column.DataType == "bit" && bitLiteralPattern.MatchString(value):
+        return value
+    case isBinaryType(column.DataType) && hexLiteralPattern.MatchString(value):
+        return value
+    case isBinaryType(column.DataType):
+        return fmt.Sprintf("X'%s'", hex.EncodeToString([]byte(value)))
+    }
+
+    value = fmt.Sprintf("'%s'", strings.NewReplacer("\\", "\\\\", "'", "\\'").Replace(value))
+
+    // TEXT、JSON 等类型只能使用表达式默认值。
+    if isExpressionDefaultType(column.DataType) {
+        return fmt.Sprintf("(%s)", value)
+    }
+
+    return value
+}
+
+// isCurrentTimestampDefault 是否为 CURRENT_TIMESTAMP 默认值。只有 TIMESTAMP 与 DATETIME 列可以不加括号使用，
+// DATE、TIME 列只能使用表达式默认值（EXTRA 含 DEFAULT_GENERATED），其余类型的 now 等是字符串。
+func isCurrentTimestampDefault(column Column) bool {
+    return lo.Contains([]string{"timestamp", "datetime"}, column.DataType) && currentTimestampPattern.MatchString(column.ColumnDefault.String)
+}
+
+// isExpressionDefaultType 是否为只能使用表达式默认值的类型。
+func isExpressionDefaultType(dataType string) bool {
+    return lo.Contains([]string{"tinytext", "text", "mediumtext", "longtext", "json", "geometry", "point", "linestring", "polygon", "multipoint", "multilinestring", "multipolygon", "geometrycollection", "geomcollection"}, dataType)
+}
+
+// isDefaultExpression 是否为表达式默认值，MySQL 5.7 与部分 TiDB 版本的 EXTRA 中没有 DEFAULT_GENERATED。
+func isDefaultExpression(column Column, tidb bool) bool {
+    if strings.Contains(strings.ToUpper(column.EXTRA), "DEFAULT_GENERATED") {
+        return true
+    }
+
+    return tidb && tidbDefaultFunctionPattern.MatchString(column.ColumnDefault.String)
+}
+
+// getDefaultExpression information_schema 中表达式默认值的引号会被转义，如 _utf8mb4\'a\'。
+func getDefaultExpression(value string) string {
+    return strings.ReplaceAll(value, "\\'", "'")
+}
+
+// getNormalizedDefault 返回用于比对的默认值，忽略 CURRENT_TIMESTAMP 的同义词、表达式的写法差异与二进制值的十六进制写法。
+func getNormalizedDefault(column Column) string {
+    value := column.ColumnDefault.String
+
+    if matches := currentTimestampPattern.FindStringSubmatch(value); matches != nil && isCurrentTimestampDefault(column) {
+        if matches[1] == "" || matches[1] == "0" {
+            return "CURRENT_TIMESTAMP"
+        }
+
+        return fmt.Sprintf("CURRENT_TIMESTAMP(%s)", matches[1])
+    }
+
+    if strings.Contains(strings.ToUpper(column.EXTRA), "DEFAULT_GENERATED") {
+        return defaultFunctionReplacer.Replace(getNormalizedSchemaExpression(getDefaultExpression(value)))
+    }
+
+    if isBinaryType(column.DataType) {
+        if hexLiteralPattern.MatchString(value) {
+            if decoded, err := hex.DecodeString(value[2:]); err == nil {
+                value = string(decoded)
+            }
+        }
+
+        // BINARY 以 0x00 补齐长度。
+        if column.DataType == "binary" {
+            value = strings.TrimRight(value, "\x00")
+        }
+    }
+
+    return value
+}
+
+// isBinaryType 是否为二进制字符串类型。
+func isBinaryType(dataType string) bool {
+    return lo.Contains([]string{"binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob"}, dataType)
+}
+
+func getColumnAfter(ordinalPosition int, columnsPos map[int]Column) string {
+    pos := ordinalPosition - 1
+
+    if _, ok := columnsPos[pos]; ok {
+        return columnsPos[pos].ColumnName
+    }
+
+    return ""
+}
+
+func getColumnPosition(after string) string {
+    if after != "" {
+        return fmt.Sprintf("AFTER `%s`", after)
+    }
+
+    return "FIRST"
+}
+
+// getColumnExtra 返回 EXTRA 中除 DEFAULT_GENERATED、生成列类型与 INVISIBLE 以外的部分，如 AUTO_INCREMENT。
+func getColumnExtra(column Column) string {
+    extra := strings.ToUpper(column.EXTRA)
+
+    for _, generated := range []string{"DEFAULT_GENERATED", "VIRTUAL GENERATED", "STORED GENERATED", "INVISIBLE"} {
+        extra = strings.Replace(extra, generated, "", 1)
+    }
+
+    extra = strings.TrimSpace(extra)
+
+    if extra != "" {
+        return fmt.Sprintf(" %s", extra)
+    }
+
+    return ""
+}
+
+// isInvisibleColumn 是否为不可见列，MySQL 8.0.23 起 EXTRA 含 INVISIBLE。
+func isInvisibleColumn(column Column) bool {
+    return lo.Contains(strings.Fields(strings.ToUpper(column.EXTRA)), "INVISIBLE")
+}
+
+// getColumnVisibility 返回不可见列的属性，与 SHOW CREATE TABLE 一致写在版本注释中。
+func getColumnVisibility(column Column) string {
+    if isInvisibleColumn(column) {
+        return " /*!80023 INVISIBLE */"
+    }
+
+    return ""
+}
+
+// getGenerationType 返回生成列的类型 VIRTUAL 或 STORED，不是生成列时返回空。
+func getGenerationType(column Column) string {
+    extra := strings.ToUpper(column.EXTRA)
+
+    switch {
+    case strings.Contains(extra, "VIRTUAL GENERATED"):
+        return "VIRTUAL"
+    case strings.Contains(extra, "STORED GENERATED"):
+        return "STORED"
+    }
+
+    return ""
+}
+
+// getColumnGenerated 返回生成列的定义，生成列没有默认值。
+func getColumnGenerated(column Column) string {
+    generated := fmt.Sprintf(" GENERATED ALWAYS AS (%s) %s", column.GenerationExpression, getGenerationType(column))
+
+    if column.IsNullable == "NO" {
+        generated += " NOT NULL"
+    }
+
+    return generated
+}
+
+// canModifyGeneration 是否可以用 MODIFY COLUMN 修改生成列类型，虚拟列与存储列不能互相转换，虚拟列与普通列也不能互相转换。
+func canModifyGeneration(from Column, to Column) bool {
+    fromType, toType := getGenerationType(from), getGenerationType(to)
+
+    return fromType == toType || (fromType != "VIRTUAL" && toType != "VIRTUAL")
+}
+
+func getColumnComment(columnComment string) string {
+    return strings.ReplaceAll(columnComment, "'", "\\'")
+}
+
+// getAddKeys 返回索引定义，索引类型与存储引擎的默认类型不同时指定 USING。
+func getAddKeys(indexName string, statisticMap map[int]Statistic, engine string) string {
+    var seqInIndexSort []int
+    var columnNames []string
+
+    for seqInIndex := range statisticMap {
+        seqInIndexSort = append(seqInIndexSort, seqInIndex)
+    }
+
+    sort.Ints(seqInIndexSort)
+
+    statistic := statisticMap[seqInIndexSort[0]]
+
+    for _, seqInIndex := range seqInIndexSort {
+        var subPart = ""
+
+        // 空间索引的 SUB_PART 由存储引擎决定，不能指定。
+        if statisticMap[seqInIndex].SubPart.Valid && statistic.IndexType != "SPATIAL" {
+            subPart = fmt.Sprintf("(%d)", statisticMap[seqInIndex].SubPart.Int32)
+        }
+
+        columnNames = append(columnNames, getKeyPart(statisticMap[seqInIndex], subPart))
+    }
+
+    using := ""
+
+    if (statistic.IndexType == "BTREE" || statistic.IndexType == "HASH") && statistic.IndexType != getDefaultIndexType(engine) {
+        using = " USING " + statistic.IndexType
+    }
+
+    visibility := getIndexVisibility(statistic)
+
+    switch {
+    case "PRIMARY" == indexName:
+        return fmt.Sprintf("PRIMARY KEY (%s)%s", strings.Join(columnNames, ","), using)
+    case "FULLTEXT" == statistic.IndexType:
+        parser := ""
+
+        if statistic.Parser != "" {
+            parser = fmt.Sprintf(" WITH PARSER `%s`", statistic.Parser)
+        }
+
+        return fmt.Sprintf("FULLTEXT KEY `%s` (%s)%s%s", indexName, strings.Join(columnNames, ","), parser, visibility)
+    case "SPATIAL" == statistic.IndexType:
+        return fmt.Sprintf("SPATIAL KEY `%s` (%s)%s", indexName, strings.Join(columnNames, ","), visibility)
+    case 0 == statistic.NonUnique:
+        return fmt.Sprintf("UNIQUE KEY `%s` (%s)%s%s", indexName, strings.Join(columnNames, ","), using, visibility)
+    }
+
+    return fmt.Sprintf("KEY `%s` (%s)%s%s", indexName, strings.Join(columnNames, ","), using, visibility)
+}
+
+// isVisibleIndex 索引是否可见，MySQL 5.7 没有 IS_VISIBLE，总是可见。
+func isVisibleIndex(statistic Statistic) bool {
+    return statistic.IsVisible.String != "NO"
+}
+
+// getIndexVisibility 返回不可见索引的属性，与 SHOW CREATE TABLE 一致写在版本注释中。
+func getIndexVisibility(statistic Statistic) string {
+    if !isVisibleIndex(statistic) {
+        return " /*!80000 INVISIBLE */"
+    }
+
+    return ""
+}
+
+// getKeyPart 返回索引的一列，函数索引的表达式写在括号中，降序时加 DESC。
+func getKeyPart(statistic Statistic, subPart string) string {
+    keyPart := fmt.Sprintf("`%s`%s", statistic.ColumnName, subPart)
+
+    if statistic.EXPRESSION.Valid {
+        keyPart = fmt.Sprintf("(%s)", statistic.EXPRESSION.String)
+    }
+
+    if isDescending(statistic) {
+        keyPart += " DESC"
+    }
+
+    return keyPart
+}
+
+// isDescending 是否为降序索引，MySQL 8.0 起 COLLATION 为 D。
+func isDescending(statistic Statistic) bool {
+    return statistic.COLLATION.String == "D"
+}
+
+// getNormalizedSchemaExpression 在 getNormalizedExpression 的基础上忽略字符串的字符集前缀与最外层的括号。
+func getNormalizedSchemaExpression(expression string) string {
+    expression = normalizeExpression(expression, true)
+
+    for strings.HasPrefix(expression, "(") && getClosingParen(expression, 1) == len(expression)-1 {
+        expression = expression[1 : len(expression)-1]
+    }
+
+    return expression
+}
+
+// getDefaultIndexType 返回存储引擎的默认索引类型，MEMORY 为 HASH，其余为 BTREE。
+func getDefaultIndexType(engine string) string {
+    if strings.EqualFold(engine, "MEMORY") || strings.EqualFold(engine, "HEAP") {
+        return "HASH"
+    }
+
+    return "BTREE"
+}
+
+// getColumnSrid 返回空间列的 SRID。
+func getColumnSrid(column Column) string {
+    if column.SrsId.Valid {
+        return fmt.Sprintf(" SRID %d", column.SrsId.Int64)
+    }
+
+    return ""
+}
+
+// getQuotedName 转义反引号中的标识符。
+func getQuotedName(name string) string {
+    return strings.ReplaceAll(name, "`", "``")
+}
+
+func getDropKey(indexName string) string {
+    if "PRIMARY" == indexName {
+        return "  DROP PRIMARY KEY"
+    }
+
+    return fmt.Sprintf("  DROP INDEX `%s`", indexName)
+}
+
+func getTableOption(option TableOptionChanged) string {
+    if lo.Contains(TableOptions, option.Name) {
+        return fmt.Sprintf("  %s", getTableOptionClause(option.Name, option.To))
+    }
+
+    switch option.Name {
+    case "COLLATE":
+        return fmt.Sprintf("  CHARACTER SET=%s, COLLATE=%s", strings.Split(option.To, "_")[0], option.To)
+    case "COMMENT":
+        return fmt.Sprintf("  COMMENT='%s'", getColumnComment(option.To))
+    }
+
+    return fmt.Sprintf("  %s=%s", option.Name, option.To)
+}
+
+// getTableOptions 返回表的扩展选项，未指定或为默认值的选项不返回，除路径与表空间外值均为大写。
+func getTableOptions(table Table) map[string]string {
+    options := make(map[string]string)
+
+    for _, matches := range createOptionPattern.FindAllStringSubmatch(table.CreateOptions.String, -1) {
+        name := strings.ToUpper(matches[1])
+
+        if lo.Contains(TableOptions, name) {
+            options[name] = strings.ToUpper(strings.Trim(matches[2], "\"'"))
+        }
+    }
+
+    if table.DataDirectory != "" {
+        options[TableOptionDataDirectory] = table.DataDirectory
+    }
+
+    if table.Tablespace != "" {
+        options[TableOptionTablespace] = table.Tablespace
+    }
+
+    for name, value := range options {
+        if value == "DEFAULT" || value == getTableOptionDefault(name) {
+            delete(options, name)
+        }
+    }
+
+    return options
+}
+
+// getTableOptionDefault 返回恢复扩展表选项默认值时指定的值。
+func getTableOptionDefault(name string) string {
+    switch name {
+    case TableOptionKeyBlockSize:
+        return "0"
+    case TableOptionCompression:
+        return "NONE"
+    case TableOptionEncryption:
+        return "N"
+    case TableOptionTablespace:
+        return "innodb_file_per_table"
+    }
+
+    return "DEFAULT"
+}
+
+// getTableOptionClause 返回扩展表选项的定义，value 为空时恢复默认值。
+func getTableOptionClause(name string, value string) string {
+    if value == "" {
+        value = getTableOptionDefault(name)
+    }
+
+    switch name {
+    case TableOptionCompression, TableOptionEncryption, TableOptionDataDirectory:
+        return fmt.Sprintf("%s='%s'", name, strings.NewReplacer("\\", "\\\\", "'", "\\'").Replace(value))
+    case TableOptionTablespace:
+        return fmt.Sprintf("TABLESPACE `%s`", getQuotedName(value))
+    }
+
+    return fmt.Sprintf("%s=%s", name, value)
+}
+
+// getCreateDatabase 返回 CREATE DATABASE 语句，name 为新建的库名。
+func getCreateDatabase(name string, schema Schema) string {
+    return fmt.Sprintf("CREATE DATABASE IF NOT EXISTS `%s` DEFAULT CHARACTER SET %s COLLATE %s%s;",
+        getQuotedName(name),
+        schema.DefaultCharacterSetName,
+        schema.DefaultCollationName,
+        getDatabaseEncryption(schema),
+    )
+}
+
+// getDatabaseEncryption 返回库的默认加密，与 SHOW CREATE DATABASE 一样写在版本注释中，以便在低版本中执行。
+func getDatabaseEncryption(schema Schema) string {
+    if !schema.DefaultEncryption.Valid {
+        return ""
+    }
+
+    return fmt.Sprintf(" /*!80016 DEFAULT ENCRYPTION='%s' */", lo.Ternary(schema.DefaultEncryption.String == "YES", "Y", "N"))
+}
+
+// getCheck 返回 CHECK 约束的定义。
+func getCheck(check Check) string {
+    return fmt.Sprintf("CONSTRAINT `%s` CHECK (%s)%s", getQuotedName(check.Constraint.ConstraintName), check.CheckClause, getCheckEnforcement(check))
+}
+
+// getAddCheck 返回新增 CHECK 约束的定义，自动生成的约束名在目标表中可能已被占用，不指定名称，由服务器重新生成。
+func getAddCheck(check Check) string {
+    if isGeneratedCheck(check) {
+        return fmt.Sprintf("CHECK (%s)%s", check.CheckClause, getCheckEnforcement(check))
+    }
+
+    return getCheck(check)
+}
+
+// getCheckEnforcement 返回 CHECK 约束是否强制检查，强制检查为默认值，不写。
+func getCheckEnforcement(check Check) string {
+    if isEnforced(check) {
+        return ""
+    }
+
+    return " NOT ENFORCED"
+}
+
+// isEnforced CHECK 约束是否强制检查，服务器没有 ENFORCED 列时视为强制检查。
+func isEnforced(check Check) bool {
+    return check.Constraint.ENFORCED != "NO"
+}
+
+// isGeneratedCheck CHECK 约束名是否为自动生成的 <表名>_chk_<n>。
+func isGeneratedCheck(check Check) bool {
+    suffix, ok := strings.CutPrefix(check.Constraint.ConstraintName, check.Constraint.TableName+"_chk_")
+
+    if !ok || suffix == "" {
+        return false
+    }
+
+    _, err := strconv.Atoi(suffix)
+
+    return err == nil
+}
+
+func getConstraint(foreignKey ForeignKey) string {
+    var (
+        columnNames           []string
+        referencedColumnNames []string
+    )
+
+    for _, keyColumnUsage := range foreignKey.KeyColumnUsages {
+        columnNames = append(columnNames, fmt.Sprintf("`%s`", keyColumnUsage.ColumnName))
+        referencedColumnNames = append(referencedColumnNames, fmt.Sprintf("`%s`", keyColumnUsage.ReferencedColumnName))
+    }
+
+    return fmt.Sprintf("CONSTRAINT `%s` FOREIGN KEY (%s) REFERENCES `%s` (%s) ON DELETE %s ON UPDATE %s",
+        foreignKey.Referential.ConstraintName,
+        strings.Join(columnNames, ","),
+        foreignKey.Referential.ReferencedTableName,
+        strings.Join(referencedColumnNames, ","),
+        foreignKey.Referential.DeleteRule, foreignKey.Referential.UpdateRule,
+    )
+}
+
+// getIndexes 按 STATISTICS 中首次出现的顺序将索引列分组。
+func getIndexes(statistics []Statistic) []Index {
+    var indexes []Index
+
+    indexMap := make(map[string]int)
+
+    for _, statistic := range statistics {
+        if i, ok := indexMap[statistic.IndexName]; ok {
+            indexes[i].Statistics[statistic.SeqInIndex] = statistic
+        } else {
+            indexMap[statistic.IndexName] = len(indexes)
+            indexes = append(indexes, Index{
+                Name:       statistic.IndexName,
+                Statistics: map[int]Statistic{statistic.SeqInIndex: statistic},
+            })
+        }
+    }
+
+    return indexes
+}
+
+// getRenamedStatistics 返回将重命名的列改为新列名后的索引列。
+func getRenamedStatistics(statistics map[int]Statistic, renamedColumns map[string]string) map[int]Statistic {
+    if len(renamedColumns) <= 0 {
+        return statistics
+    }
+
+    renamed := make(map[int]Statistic)
+
+    for seqInIndex, statistic := range statistics {
+        if newName, ok := renamedColumns[statistic.ColumnName]; ok {
+            statistic.ColumnName = newName
+        }
+
+        renamed[seqInIndex] = statistic
+    }
+
+    return renamed
+}
+
+// getViewDefinition 去掉视图定义中的库名前缀。
+func getViewDefinition(view View, database string) string {
+    return strings.Replace(view.ViewDefinition, fmt.Sprintf("`%s`.", database), "", -1)
+}
+
+func getCharacterSet(sourceColumn Column, targetColumn Column) string {
+    if sourceColumn.CharacterSetName.Valid && sourceColumn.CollationName.Valid {
+        condition := false
+
+        if sourceColumn.CharacterSetName.String != targetColumn.CharacterSetName.String {
+            condition = true
+        }
+
+        if sourceColumn.CollationName.String != targetColumn.CollationName.String {
+            condition = true
+        }
+
+        if sourceColumn == targetColumn {
+            condition = true
+        }
+
+        if condition {
+            return fmt.Sprintf(" CHARACTER SET %s COLLATE %s", sourceColumn.CharacterSetName.String, sourceColumn.CollationName.String)
+        }
+    }
+
+    return ""
+}
+
+// getTriggerGroup 返回触发器所属的表、时机、事件。
+func getTriggerGroup(trigger Trigger) string {
+    return fmt.Sprintf("%s.%s.%s", trigger.EventObjectTable, trigger.ActionTiming, trigger.EventManipulation)
+}
+
+// getDefiner 将 user@host 转换为 `user`@`host`。
+func getDefiner(definer string) string {
+    i := strings.LastIndex(definer, "@")
+
+    if i < 0 {
+        return fmt.Sprintf("`%s`", definer)
+    }
+
+    return fmt.Sprintf("`%s`@`%s`", definer[:i], definer[i+1:])
+}
+
+// getRoutineKey 存储过程与函数可以同名，按类型区分。
+func getRoutineKey(routineType string, name string) string {
+    return routineType + "." + name
+}
+
+// getRoutineParameters 返回参数列表，如 IN `id` int, OUT `name` varchar(64)。
+func getRoutineParameters(parameters []Parameter) string {
+    var definitions []string
+
+    for _, parameter := range parameters {
+        definition := fmt.Sprintf("`%s` %s", parameter.ParameterName.String, parameter.DtdIdentifier)
+
+        // 函数的参数没有 PARAMETER_MODE。
+        if parameter.RoutineType == "PROCEDURE" && parameter.ParameterMode.Valid {
+            definition = parameter.ParameterMode.String + " " + definition
+        }
+
+        definitions = append(definitions, definition)
+    }
+
+    return strings.Join(definitions, ", ")
+}
+
+// getEventSchedule 返回 ON SCHEDULE 之后的部分，starts、ends 为 false 时不含 STARTS、ENDS。
+func getEventSchedule(event Event, starts bool, ends bool) string {
+    if event.EventType == "ONE TIME" {
+        return fmt.Sprintf("AT '%s'", event.ExecuteAt.Time.Format(time.DateTime))
+    }
+
+    interval := event.IntervalValue.String
+
+    // 复合单位的值如 '1:30' HOUR_MINUTE 需要加引号。
+    if _, err := strconv.ParseInt(interval, 10, 64); err != nil {
+        interval = fmt.Sprintf("'%s'", interval)
+    }
+
+    schedule := fmt.Sprintf("EVERY %s %s", interval, event.IntervalField.String)
+
+    if starts && event.STARTS.Valid {
+        schedule += fmt.Sprintf(" STARTS '%s'", event.STARTS.Time.Format(time.DateTime))
+    }
+
+    if ends && event.ENDS.Valid {
+        schedule += fmt.Sprintf(" ENDS '%s'", event.ENDS.Time.Format(time.DateTime))
+    }
+
+    return schedule
+}
+
+// getEventStatus 将 STATUS 转换为 CREATE EVENT 中的写法。
+func getEventStatus(status string) string {
+    switch status {
+    case "DISABLED":
+        return "DISABLE"
+    case "SLAVESIDE_DISABLED":
+        return "DISABLE ON SLAVE"
+    }
+
+    return "ENABLE"
+}
+
+// getPartitionDefinitions 返回分区定义，有子分区时每个分区只取第一行。
+func getPartitionDefinitions(partitions []Partition) []Partition {
+    var definitions []Partition
+
+    for _, partition := range partitions {
+        if len(definitions) > 0 && definitions[len(definitions)-1].PartitionName.String == partition.PartitionName.String {
+            continue
+        }
+
+        definitions = append(definitions, partition)
+    }
+
+    return definitions
+}
+
+// getSubpartitionCount 返回每个分区的子分区数。
+func getSubpartitionCount(partitions []Partition) int {
+    definitions := getPartitionDefinitions(partitions)
+
+    if len(definitions) <= 0 || !definitions[0].SubpartitionName.Valid {
+        return 0
+    }
+
+    return len(partitions) / len(definitions)
+}
+
+// isPartitionRange RANGE 与 LIST 分区逐个定义，HASH 与 KEY 分区只需分区数。
+func isPartitionRange(method string) bool {
+    return strings.HasPrefix(method, "RANGE") || strings.HasPrefix(method, "LIST")
+}
+
+// getPartitionScheme 返回分区方式，用于判断是否需要重新分区。
+func getPartitionScheme(partitions []Partition) string {
+    definitions := getPartitionDefinitions(partitions)
+
+    if len(definitions) <= 0 {
+        return ""
+    }
+
+    scheme := fmt.Sprintf("%s(%s)", definitions[0].PartitionMethod.String, getNormalizedExpression(definitions[0].PartitionExpression.String))
+
+    if !isPartitionRange(definitions[0].PartitionMethod.String) {
+        scheme += fmt.Sprintf(" PARTITIONS %d", len(definitions))
+    }
+
+    if definitions[0].SubpartitionMethod.Valid {
+        scheme += fmt.Sprintf(" SUBPARTITION BY %s(%s) SUBPARTITIONS %d",
+            definitions[0].SubpartitionMethod.String,
+            getNormalizedExpression(definitions[0].SubpartitionExpression.String),
+            getSubpartitionCount(partitions),
+        )
+    }
+
+    return scheme
+}
+
+// getNormalizedExpression 忽略大小写、空白、反引号与不等号写法（<> 与 !=）的差异，字符串的内容保持不变。
+func getNormalizedExpression(expression string) string {
+    return normalizeExpression(expression, false)
+}
+
+// normalizeExpression 逐段处理表达式：字符串统一写作单引号并保留内容，标识符去掉反引号并忽略大小写，
+// 其余部分忽略大小写与空白；introducer 时去掉字符串的字符集前缀。
+func normalizeExpression(expression string, introducer bool) string {
+    var b strings.Builder
+
+    for i := 0; i < len(expression); {
+        switch c := expression[i]; c {
+        case '\'', '"':
+            value, end := getStringLiteral(expression, i)
+            b.WriteString("'" + strings.NewReplacer("\\", "\\\\", "'", "\\'").Replace(value) + "'")
+            i = end
+        case '`':
+            name, end := getBacktickIdentifier(expression, i)
+            b.WriteString(strings.ToLower(name))
+            i = end
+        default:
+            end := i + strings.IndexAny(expression[i:], "'\"`")
+
+            if end < i {
+                end = len(expression)
+            }
+
+            segment := expression[i:end]
+
+            if introducer && end < len(expression) && expression[end] != '`' {
+                segment = charsetIntroducerPattern.ReplaceAllString(segment, "")
+            }
+
+            b.WriteString(expressionReplacer.Replace(strings.ToLower(strings.Join(strings.Fields(segment), ""))))
+            i = end
+        }
+    }
+
+    return b.String()
+}
+
+// getStringLiteral 返回从 start 开始的字符串的内容与结束位置，处理反斜杠转义与连续两个引号。
+func getStringLiteral(text string, start int) (string, int) {
+    var (
+        b     strings.Builder
+        quote = text[start]
+    )
+
+    for i := start + 1; i < len(text); i++ {
+        c := text[i]
+
+        switch {
+        case c == '\\' && i+1 < len(text):
+            i++
+            b.WriteByte(getUnescapedByte(text[i]))
+        case c == quote && i+1 < len(text) && text[i+1] == quote:
+            i++
+            b.WriteByte(quote)
+        case c == quote:
+            return b.String(), i + 1
+        default:
+            b.WriteByte(c)
+        }
+    }
+
+    return b.String(), len(text)
+}
+
+// getUnescapedByte 返回反斜杠转义的字符。
+func getUnescapedByte(c byte) byte {
+    switch c {
+    case '0':
+        return 0
+    case 'n':
+        return '\n'
+    case 'r':
+        return '\r'
+    case 't':
+        return '\t'
+    case 'Z':
+        return 26
+    case 'b':
+        return '\b'
+    }
+
+    return c
+}
+
+// getBacktickIdentifier 返回从 start 开始的反引号标识符与结束位置，两个反引号表示一个反引号。
+func getBacktickIdentifier(text string, start int) (string, int) {
+    var b strings.Builder
+
+    for i := start + 1; i < len(text); i++ {
+        if text[i] != '`' {
+            b.WriteByte(text[i])
+        } else if i+1 < len(text) && text[i+1] == '`' {
+            i++
+            b.WriteByte('`')
+        } else {
+            return b.String(), i + 1
+        }
+    }
+
+    return b.String(), len(text)
+}
+
+// getPartitionBy 返回 PARTITION BY 子句。
+func getPartitionBy(partitions []Partition) string {
+    definitions := getPartitionDefinitions(partitions)
+    first := definitions[0]
+
+    partitionBy := fmt.Sprintf("PARTITION BY %s (%s)", first.PartitionMethod.String, first.PartitionExpression.String)
+
+    if first.SubpartitionMethod.Valid {
+        partitionBy += fmt.Sprintf("\nSUBPARTITION BY %s (%s)\nSUBPARTITIONS %d",
+            first.SubpartitionMethod.String,
+            first.SubpartitionExpression.String,
+            getSubpartitionCount(partitions),
+        )
+    }
+
+    if !isPartitionRange(first.PartitionMethod.String) {
+        return partitionBy + fmt.Sprintf("\nPARTITIONS %d", len(definitions))
+    }
+
+    return partitionBy + fmt.Sprintf("\n(%s)", strings.Join(getPartitionDefinitionSql(definitions), ",\n "))
+}
+
+// getPartitionDefinitionSql 返回分区定义，如 PARTITION `p0` VALUES LESS THAN (10)。
+func getPartitionDefinitionSql(definitions []Partition) []string {
+    var sqls []string
+
+    for _, definition := range definitions {
+        partitionSql := fmt.Sprintf("PARTITION `%s`", definition.PartitionName.String)
+
+        switch {
+        case strings.HasPrefix(definition.PartitionMethod.String, "LIST"):
+            partitionSql += fmt.Sprintf(" VALUES IN (%s)", definition.PartitionDescription.String)
+        case definition.PartitionMethod.String == "RANGE" && definition.PartitionDescription.String == "MAXVALUE":
+            partitionSql += " VALUES LESS THAN MAXVALUE"
+        case strings.HasPrefix(definition.PartitionMethod.String, "RANGE"):
+            partitionSql += fmt.Sprintf(" VALUES LESS THAN (%s)", definition.PartitionDescription.String)
+        }
+
+        if definition.PartitionComment != "" {
+            partitionSql += fmt.Sprintf(" COMMENT = '%s'", getColumnComment(definition.PartitionComment))
+        }
+
+        sqls = append(sqls, partitionSql)
+    }
+
+    return sqls
+}
+
+// getPartitionNames 返回以逗号分隔的分区名。
+func getPartitionNames(definitions []Partition) string {
+    var names []string
+
+    for _, definition := range definitions {
+        names = append(names, fmt.Sprintf("`%s`", definition.PartitionName.String))
+    }
+
+    return strings.Join(names, ", ")
+}
+
+// getPartitionValues 按顶层逗号拆分分区值，如 1,(2,'a'),'b,c' 拆为 1、(2,'a')、'b,c'。
+func getPartitionValues(description string) []string {
+    var (
+        values []string
+        depth  int
+        quote  rune
+        start  int
+    )
+
+    for i, r := range description {
+        switch {
+        case quote != 0:
+            if r == quote {
+                quote = 0
+            }
+        case r == '\'' || r == '"':
+            quote = r
+        case r == '(':
+            depth++
+        case r == ')':
+            depth--
+        case r == ',' && depth == 0:
+            values = append(values, strings.ReplaceAll(strings.TrimSpace(description[start:i]), ", ", ","))
+            start = i + 1
+        }
+    }
+
+    if strings.TrimSpace(description) != "" {
+        values = append(values, strings.ReplaceAll(strings.TrimSpace(description[start:]), ", ", ","))
+    }
+
+    return values
+}
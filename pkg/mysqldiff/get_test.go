@@ -0,0 +1,158 @@
+package mysqldiff
+
+import (
+    "database/sql"
+    "slices"
+    "testing"
+)
+
+func TestGetNormalizedSchemaExpression(t *testing.T) {
+    tests := []struct {
+        name  string
+        a     string
+        b     string
+        equal bool
+    }{
+        {"大小写与空白", "CONCAT(`a`, `b`)", "concat(a,b)", true},
+        {"字符集前缀", "concat(`a`,_utf8mb4' x')", "CONCAT(a, ' x')", true},
+        {"字符集前缀与空白", "json_extract(`c`,_utf8mb4 '$.a')", "json_extract(c,'$.a')", true},
+        {"最外层括号", "((`a` > 1))", "a>1", true},
+        {"不等号", "(`c1` <> 0)", "(`c1`!=0)", true},
+        {"转义写法", "concat(a,'it''s')", "concat(a,'it\\'s')", true},
+        {"双引号字符串", "concat(a,\"x\")", "concat(a,'x')", true},
+        {"字符串大小写", "concat(a,'A B')", "concat(a,'a b')", false},
+        {"字符串中的空白", "concat(a,' x')", "concat(a,'x')", false},
+        {"字符串中的空格数", "concat(a,'A B')", "concat(a,'AB')", false},
+        {"字符串中的不等号", "concat(a,'<>')", "concat(a,'!=')", false},
+        {"字符串中的反引号", "concat(a,'`x`')", "concat(a,'x')", false},
+        {"字符串中的字符集前缀", "concat(a,'_utf8mb4''x')", "concat(a,'''x')", false},
+        {"标识符中的空格", "`my col` + 1", "`mycol` + 1", false},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            a, b := getNormalizedSchemaExpression(tt.a), getNormalizedSchemaExpression(tt.b)
+
+            if (a == b) != tt.equal {
+                t.Errorf("getNormalizedSchemaExpression(%q) = %q, getNormalizedSchemaExpression(%q) = %q, equal = %v, want %v", tt.a, a, tt.b, b, a == b, tt.equal)
+            }
+        })
+    }
+}
+
+func TestGetNormalizedExpression(t *testing.T) {
+    tests := []struct {
+        expression string
+        want       string
+    }{
+        {"TO_DAYS(`created_at`)", "to_days(created_at)"},
+        {"`a` <> 'A B'", "a!='A B'"},
+        {"`A`, \"x\"", "a,'x'"},
+    }
+
+    for _, tt := range tests {
+        if got := getNormalizedExpression(tt.expression); got != tt.want {
+            t.Errorf("getNormalizedExpression(%q) = %q, want %q", tt.expression, got, tt.want)
+        }
+    }
+}
+
+func TestGetColumnDefault(t *testing.T) {
+    tests := []struct {
+        name   string
+        column Column
+        tidb   bool
+        want   string
+    }{
+        {"CURRENT_TIMESTAMP", Column{DataType: "timestamp", ColumnDefault: sql.NullString{String: "CURRENT_TIMESTAMP", Valid: true}, EXTRA: "DEFAULT_GENERATED"}, false, "CURRENT_TIMESTAMP"},
+        {"精度", Column{DataType: "datetime", ColumnDefault: sql.NullString{String: "CURRENT_TIMESTAMP(3)", Valid: true}, EXTRA: "DEFAULT_GENERATED"}, false, "CURRENT_TIMESTAMP(3)"},
+        {"同义词", Column{DataType: "datetime", ColumnDefault: sql.NullString{String: "now()", Valid: true}}, false, "NOW()"},
+        {"字符串 now", Column{DataType: "varchar", ColumnDefault: sql.NullString{String: "now", Valid: true}}, false, "'now'"},
+        {"字符串 current_timestamp", Column{DataType: "char", ColumnDefault: sql.NullString{String: "current_timestamp", Valid: true}}, false, "'current_timestamp'"},
+        {"TEXT 字符串 NOW()", Column{DataType: "text", ColumnDefault: sql.NullString{String: "NOW()", Valid: true}}, false, "('NOW()')"},
+        {"表达式默认值", Column{DataType: "date", ColumnDefault: sql.NullString{String: "curdate()", Valid: true}, EXTRA: "DEFAULT_GENERATED"}, false, "(curdate())"},
+        {"TiDB 表达式默认值", Column{DataType: "varchar", ColumnDefault: sql.NullString{String: "uuid()", Valid: true}}, true, "(uuid())"},
+        {"转义", Column{DataType: "varchar", ColumnDefault: sql.NullString{String: "it's", Valid: true}}, false, "'it\\'s'"},
+        {"BIT", Column{DataType: "bit", ColumnDefault: sql.NullString{String: "b'101'", Valid: true}}, false, "b'101'"},
+        {"二进制", Column{DataType: "varbinary", ColumnDefault: sql.NullString{String: "ab", Valid: true}}, false, "X'6162'"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := getColumnDefault(tt.column, tt.tidb); got != tt.want {
+                t.Errorf("getColumnDefault() = %q, want %q", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestGetNormalizedDefault(t *testing.T) {
+    tests := []struct {
+        name  string
+        a     Column
+        b     Column
+        equal bool
+    }{
+        {
+            "CURRENT_TIMESTAMP 的同义词",
+            Column{DataType: "timestamp", ColumnDefault: sql.NullString{String: "CURRENT_TIMESTAMP", Valid: true}},
+            Column{DataType: "timestamp", ColumnDefault: sql.NullString{String: "now()", Valid: true}},
+            true,
+        },
+        {
+            "精度",
+            Column{DataType: "datetime", ColumnDefault: sql.NullString{String: "CURRENT_TIMESTAMP(3)", Valid: true}},
+            Column{DataType: "datetime", ColumnDefault: sql.NullString{String: "CURRENT_TIMESTAMP", Valid: true}},
+            false,
+        },
+        {
+            "字符串 now 与 current_timestamp",
+            Column{DataType: "varchar", ColumnDefault: sql.NullString{String: "now", Valid: true}},
+            Column{DataType: "varchar", ColumnDefault: sql.NullString{String: "current_timestamp", Valid: true}},
+            false,
+        },
+        {
+            "表达式默认值的写法",
+            Column{DataType: "varchar", ColumnDefault: sql.NullString{String: "concat(_utf8mb4\\'a\\',_utf8mb4\\'b\\')", Valid: true}, EXTRA: "DEFAULT_GENERATED"},
+            Column{DataType: "varchar", ColumnDefault: sql.NullString{String: "CONCAT('a', 'b')", Valid: true}, EXTRA: "DEFAULT_GENERATED"},
+            true,
+        },
+        {
+            "二进制的十六进制写法",
+            Column{DataType: "binary", ColumnDefault: sql.NullString{String: "0x616200", Valid: true}},
+            Column{DataType: "binary", ColumnDefault: sql.NullString{String: "ab", Valid: true}},
+            true,
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            a, b := getNormalizedDefault(tt.a), getNormalizedDefault(tt.b)
+
+            if (a == b) != tt.equal {
+                t.Errorf("getNormalizedDefault() = %q, %q, equal = %v, want %v", a, b, a == b, tt.equal)
+            }
+        })
+    }
+}
+
+func TestGetPartitionValues(t *testing.T) {
+    tests := []struct {
+        name        string
+        description string
+        want        []string
+    }{
+        {"空", "", nil},
+        {"整数", "1,2, 3", []string{"1", "2", "3"}},
+        {"多列", "(1, 'a'),(2,'b')", []string{"(1,'a')", "(2,'b')"}},
+        {"字符串中的逗号", "'a,b','c'", []string{"'a,b'", "'c'"}},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := getPartitionValues(tt.description); !slices.Equal(got, tt.want) {
+                t.Errorf("getPartitionValues() = %q, want %q", got, tt.want)
+            }
+        })
+    }
+}
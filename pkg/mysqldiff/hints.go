@@ -0,0 +1,71 @@
+package mysqldiff
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+)
+
+// Hints 重命名提示，用于无法自动判断的重命名，键为旧名（目标），值为新名（源）。
+//
+//  {
+//      "tables": {"old_table": "new_table"},
+//      "columns": {"new_table": {"old_column": "new_column"}}
+//  }
+//
+// Columns 的键为源表名。
+type Hints struct {
+    Tables  map[string]string            `json:"tables"`
+    Columns map[string]map[string]string `json:"columns"`
+}
+
+// ReadHintsFile 读取重命名提示文件。
+func ReadHintsFile(name string) (Hints, error) {
+    var hints Hints
+
+    content, err := os.ReadFile(name)
+
+    if err != nil {
+        return hints, err
+    }
+
+    if err := json.Unmarshal(content, &hints); err != nil {
+        return hints, fmt.Errorf("重命名提示格式错误：%w", err)
+    }
+
+    return hints, nil
+}
+
+// Reverse 返回反方向的提示，用于回滚。
+func (h Hints) Reverse() Hints {
+    reverse := Hints{
+        Tables:  make(map[string]string),
+        Columns: make(map[string]map[string]string),
+    }
+
+    for oldName, newName := range h.Tables {
+        reverse.Tables[newName] = oldName
+    }
+
+    for tableName, columns := range h.Columns {
+        // 回滚时源表为旧表名。
+        if oldTableName, ok := reverse.Tables[tableName]; ok {
+            tableName = oldTableName
+        }
+
+        reverse.Columns[tableName] = make(map[string]string)
+
+        for oldName, newName := range columns {
+            reverse.Columns[tableName][newName] = oldName
+        }
+    }
+
+    return reverse
+}
+
+// column 返回表 tableName 中旧列名 oldName 的新列名。
+func (h Hints) column(tableName string, oldName string) (string, bool) {
+    newName, ok := h.Columns[tableName][oldName]
+
+    return newName, ok
+}
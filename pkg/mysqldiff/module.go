@@ -0,0 +1,287 @@
+package mysqldiff
+
+import "database/sql"
+
+type DbConfig struct {
+    User     string
+    Password string
+    Host     string
+    Port     int
+    Database string
+    Charset  string
+}
+
+type Schema struct {
+    CatalogName             string         `gorm:"column:CATALOG_NAME"`
+    SchemaName              string         `gorm:"column:SCHEMA_NAME"`
+    DefaultCharacterSetName string         `gorm:"column:DEFAULT_CHARACTER_SET_NAME"`
+    DefaultCollationName    string         `gorm:"column:DEFAULT_COLLATION_NAME"`
+    SqlPath                 sql.NullString `gorm:"column:SQL_PATH"`
+    DefaultEncryption       sql.NullString `gorm:"column:DEFAULT_ENCRYPTION"` // MySQL 8.0.16 起
+
+    // 在 information_schema.SCHEMATA_EXTENSIONS 中（MySQL 8.0.22 起），见 Catalog.loadReadOnly。
+    ReadOnly bool `gorm:"-"`
+}
+
+type Table struct {
+    TableCatalog   string         `gorm:"column:TABLE_CATALOG"`
+    TableSchema    string         `gorm:"column:TABLE_SCHEMA"`
+    TableName      string         `gorm:"column:TABLE_NAME"`
+    TableType      string         `gorm:"column:TABLE_TYPE"`
+    ENGINE         sql.NullString `gorm:"column:ENGINE"`
+    VERSION        sql.NullInt64  `gorm:"column:VERSION"`
+    RowFormat      sql.NullString `gorm:"column:ROW_FORMAT"`
+    TableRows      sql.NullInt64  `gorm:"column:TABLE_ROWS"`
+    AvgRowLength   sql.NullInt64  `gorm:"column:AVG_ROW_LENGTH"`
+    DataLength     sql.NullInt64  `gorm:"column:DATA_LENGTH"`
+    MaxDataLength  sql.NullInt64  `gorm:"column:MAX_DATA_LENGTH"`
+    IndexLength    sql.NullInt64  `gorm:"column:INDEX_LENGTH"`
+    DataFree       sql.NullInt64  `gorm:"column:DATA_FREE"`
+    AutoIncrement  sql.NullInt64  `gorm:"column:AUTO_INCREMENT"`
+    CreateTime     sql.NullTime   `gorm:"column:CREATE_TIME"`
+    UpdateTime     sql.NullTime   `gorm:"column:UPDATE_TIME"`
+    CheckTime      sql.NullTime   `gorm:"column:CHECK_TIME"`
+    TableCollation sql.NullString `gorm:"column:TABLE_COLLATION"`
+    CHECKSUM       sql.NullInt64  `gorm:"column:CHECKSUM"`
+    CreateOptions  sql.NullString `gorm:"column:CREATE_OPTIONS"`
+    TableComment   string         `gorm:"column:TABLE_COMMENT"`
+
+    // information_schema 中没有，按需从 SHOW CREATE TABLE 中读取，见 Catalog.LoadStorage。
+    DataDirectory string `gorm:"-"`
+    Tablespace    string `gorm:"-"`
+}
+
+type Column struct {
+    TableCatalog           string         `gorm:"column:TABLE_CATALOG"`
+    TableSchema            string         `gorm:"column:TABLE_SCHEMA"`
+    TableName              string         `gorm:"column:TABLE_NAME"`
+    ColumnName             string         `gorm:"column:COLUMN_NAME"`
+    OrdinalPosition        int            `gorm:"column:ORDINAL_POSITION"`
+    ColumnDefault          sql.NullString `gorm:"column:COLUMN_DEFAULT"`
+    IsNullable             string         `gorm:"column:IS_NULLABLE"`
+    DataType               string         `gorm:"column:DATA_TYPE"`
+    CharacterMaximumLength sql.NullInt64  `gorm:"column:CHARACTER_MAXIMUM_LENGTH"`
+    CharacterOctetLength   sql.NullInt64  `gorm:"column:CHARACTER_OCTET_LENGTH"`
+    NumericPrecision       sql.NullInt64  `gorm:"column:NUMERIC_PRECISION"`
+    NumericScale           sql.NullInt64  `gorm:"column:NUMERIC_SCALE"`
+    DatetimePrecision      sql.NullInt64  `gorm:"column:DATETIME_PRECISION"`
+    CharacterSetName       sql.NullString `gorm:"column:CHARACTER_SET_NAME"`
+    CollationName          sql.NullString `gorm:"column:COLLATION_NAME"`
+    ColumnType             string         `gorm:"column:COLUMN_TYPE"`
+    ColumnKey              string         `gorm:"column:COLUMN_KEY"`
+    EXTRA                  string         `gorm:"column:EXTRA"`
+    PRIVILEGES             string         `gorm:"column:PRIVILEGES"`
+    ColumnComment          string         `gorm:"column:COLUMN_COMMENT"`
+    GenerationExpression   string         `gorm:"column:GENERATION_EXPRESSION"`
+    SrsId                  sql.NullInt64  `gorm:"column:SRS_ID"`
+}
+
+type Statistic struct {
+    TableCatalog string         `gorm:"column:TABLE_CATALOG"`
+    TableSchema  string         `gorm:"column:TABLE_SCHEMA"`
+    TableName    string         `gorm:"column:TABLE_NAME"`
+    NonUnique    int64          `gorm:"column:NON_UNIQUE"`
+    IndexSchema  string         `gorm:"column:INDEX_SCHEMA"`
+    IndexName    string         `gorm:"column:INDEX_NAME"`
+    SeqInIndex   int            `gorm:"column:SEQ_IN_INDEX"`
+    ColumnName   string         `gorm:"column:COLUMN_NAME"`
+    COLLATION    sql.NullString `gorm:"column:COLLATION"`
+    CARDINALITY  sql.NullInt64  `gorm:"column:CARDINALITY"`
+    SubPart      sql.NullInt32  `gorm:"column:SUB_PART"`
+    PACKED       sql.NullString `gorm:"column:PACKED"`
+    NULLABLE     string         `gorm:"column:NULLABLE"`
+    IndexType    string         `gorm:"column:INDEX_TYPE"`
+    COMMENT      sql.NullString `gorm:"column:COMMENT"`
+    IndexComment string         `gorm:"column:INDEX_COMMENT"`
+    IsVisible    sql.NullString `gorm:"column:IS_VISIBLE"`
+    EXPRESSION   sql.NullString `gorm:"column:EXPRESSION"`
+
+    // Parser 全文索引的解析器（WITH PARSER），information_schema 中没有，读取自 SHOW CREATE TABLE。
+    Parser string `gorm:"-"`
+}
+
+type View struct {
+    TableCatalog        string `gorm:"column:TABLE_CATALOG"`
+    TableSchema         string `gorm:"column:TABLE_SCHEMA"`
+    TableName           string `gorm:"column:TABLE_NAME"`
+    ViewDefinition      string `gorm:"column:VIEW_DEFINITION"`
+    CheckOption         string `gorm:"column:CHECK_OPTION"`
+    IsUpdatable         string `gorm:"column:IS_UPDATABLE"`
+    DEFINER             string `gorm:"column:DEFINER"`
+    SecurityType        string `gorm:"column:SECURITY_TYPE"`
+    CharacterSetClient  string `gorm:"column:CHARACTER_SET_CLIENT"`
+    CollationConnection string `gorm:"column:COLLATION_CONNECTION"`
+}
+
+type TableConstraints struct {
+    ConstraintCatalog string `gorm:"column:CONSTRAINT_CATALOG"`
+    ConstraintSchema  string `gorm:"column:CONSTRAINT_SCHEMA"`
+    ConstraintName    string `gorm:"column:CONSTRAINT_NAME"`
+    TableSchema       string `gorm:"column:TABLE_SCHEMA"`
+    TableName         string `gorm:"column:TABLE_NAME"`
+    ConstraintType    string `gorm:"column:CONSTRAINT_TYPE"`
+    ENFORCED          string `gorm:"column:ENFORCED"` // MySQL 8.0.16 起
+}
+
+// CheckConstraints MySQL 8.0.16 起，CHECK_CLAUSE 含最外层的括号。
+type CheckConstraints struct {
+    ConstraintCatalog string `gorm:"column:CONSTRAINT_CATALOG"`
+    ConstraintSchema  string `gorm:"column:CONSTRAINT_SCHEMA"`
+    ConstraintName    string `gorm:"column:CONSTRAINT_NAME"`
+    CheckClause       string `gorm:"column:CHECK_CLAUSE"`
+}
+
+type ReferentialConstraints struct {
+    ConstraintCatalog       string `gorm:"column:CONSTRAINT_CATALOG"`
+    ConstraintSchema        string `gorm:"column:CONSTRAINT_SCHEMA"`
+    ConstraintName          string `gorm:"column:CONSTRAINT_NAME"`
+    UniqueConstraintCatalog string `gorm:"column:UNIQUE_CONSTRAINT_CATALOG"`
+    UniqueConstraintSchema  string `gorm:"column:UNIQUE_CONSTRAINT_SCHEMA"`
+    UniqueConstraintName    string `gorm:"column:UNIQUE_CONSTRAINT_NAME"`
+    MatchOption             string `gorm:"column:MATCH_OPTION"`
+    UpdateRule              string `gorm:"column:UPDATE_RULE"`
+    DeleteRule              string `gorm:"column:DELETE_RULE"`
+    TableName               string `gorm:"column:TABLE_NAME"`
+    ReferencedTableName     string `gorm:"column:REFERENCED_TABLE_NAME"`
+}
+
+type KeyColumnUsage struct {
+    ConstraintCatalog          string `gorm:"column:CONSTRAINT_CATALOG"`
+    ConstraintSchema           string `gorm:"column:CONSTRAINT_SCHEMA"`
+    ConstraintName             string `gorm:"column:CONSTRAINT_NAME"`
+    TableCatalog               string `gorm:"column:TABLE_CATALOG"`
+    TableSchema                string `gorm:"column:TABLE_SCHEMA"`
+    TableName                  string `gorm:"column:TABLE_NAME"`
+    ColumnName                 string `gorm:"column:COLUMN_NAME"`
+    OrdinalPosition            int64  `gorm:"column:ORDINAL_POSITION"`
+    PositionInUniqueConstraint int64  `gorm:"column:POSITION_IN_UNIQUE_CONSTRAINT"`
+    ReferencedTableSchema      string `gorm:"column:REFERENCED_TABLE_SCHEMA"`
+    ReferencedTableName        string `gorm:"column:REFERENCED_TABLE_NAME"`
+    ReferencedColumnName       string `gorm:"column:REFERENCED_COLUMN_NAME"`
+}
+
+type Trigger struct {
+    TriggerCatalog          string         `gorm:"column:TRIGGER_CATALOG"`
+    TriggerSchema           string         `gorm:"column:TRIGGER_SCHEMA"`
+    TriggerName             string         `gorm:"column:TRIGGER_NAME"`
+    EventManipulation       string         `gorm:"column:EVENT_MANIPULATION"`
+    EventObjectCatalog      string         `gorm:"column:EVENT_OBJECT_CATALOG"`
+    EventObjectSchema       string         `gorm:"column:EVENT_OBJECT_SCHEMA"`
+    EventObjectTable        string         `gorm:"column:EVENT_OBJECT_TABLE"`
+    ActionOrder             int64          `gorm:"column:ACTION_ORDER"`
+    ActionCondition         sql.NullString `gorm:"column:ACTION_CONDITION"`
+    ActionStatement         string         `gorm:"column:ACTION_STATEMENT"`
+    ActionOrientation       string         `gorm:"column:ACTION_ORIENTATION"`
+    ActionTiming            string         `gorm:"column:ACTION_TIMING"`
+    ActionReferenceOldTable sql.NullString `gorm:"column:ACTION_REFERENCE_OLD_TABLE"`
+    ActionReferenceNewTable sql.NullString `gorm:"column:ACTION_REFERENCE_NEW_TABLE"`
+    ActionReferenceOldRow   string         `gorm:"column:ACTION_REFERENCE_OLD_ROW"`
+    ActionReferenceNewRow   string         `gorm:"column:ACTION_REFERENCE_NEW_ROW"`
+    CREATED                 sql.NullTime   `gorm:"column:CREATED"`
+    SqlMode                 string         `gorm:"column:SQL_MODE"`
+    DEFINER                 string         `gorm:"column:DEFINER"`
+    CharacterSetClient      string         `gorm:"column:CHARACTER_SET_CLIENT"`
+    CollationConnection     string         `gorm:"column:COLLATION_CONNECTION"`
+    DatabaseCollation       string         `gorm:"column:DATABASE_COLLATION"`
+}
+
+type Routine struct {
+    SpecificName        string         `gorm:"column:SPECIFIC_NAME"`
+    RoutineCatalog      string         `gorm:"column:ROUTINE_CATALOG"`
+    RoutineSchema       string         `gorm:"column:ROUTINE_SCHEMA"`
+    RoutineName         string         `gorm:"column:ROUTINE_NAME"`
+    RoutineType         string         `gorm:"column:ROUTINE_TYPE"`
+    DataType            string         `gorm:"column:DATA_TYPE"`
+    CharacterSetName    sql.NullString `gorm:"column:CHARACTER_SET_NAME"`
+    CollationName       sql.NullString `gorm:"column:COLLATION_NAME"`
+    DtdIdentifier       sql.NullString `gorm:"column:DTD_IDENTIFIER"`
+    RoutineBody         string         `gorm:"column:ROUTINE_BODY"`
+    RoutineDefinition   sql.NullString `gorm:"column:ROUTINE_DEFINITION"`
+    ParameterStyle      string         `gorm:"column:PARAMETER_STYLE"`
+    IsDeterministic     string         `gorm:"column:IS_DETERMINISTIC"`
+    SqlDataAccess       string         `gorm:"column:SQL_DATA_ACCESS"`
+    SecurityType        string         `gorm:"column:SECURITY_TYPE"`
+    CREATED             sql.NullTime   `gorm:"column:CREATED"`
+    LastAltered         sql.NullTime   `gorm:"column:LAST_ALTERED"`
+    SqlMode             string         `gorm:"column:SQL_MODE"`
+    RoutineComment      string         `gorm:"column:ROUTINE_COMMENT"`
+    DEFINER             string         `gorm:"column:DEFINER"`
+    CharacterSetClient  string         `gorm:"column:CHARACTER_SET_CLIENT"`
+    CollationConnection string         `gorm:"column:COLLATION_CONNECTION"`
+    DatabaseCollation   string         `gorm:"column:DATABASE_COLLATION"`
+}
+
+type Parameter struct {
+    SpecificCatalog  string         `gorm:"column:SPECIFIC_CATALOG"`
+    SpecificSchema   string         `gorm:"column:SPECIFIC_SCHEMA"`
+    SpecificName     string         `gorm:"column:SPECIFIC_NAME"`
+    OrdinalPosition  int            `gorm:"column:ORDINAL_POSITION"`
+    ParameterMode    sql.NullString `gorm:"column:PARAMETER_MODE"`
+    ParameterName    sql.NullString `gorm:"column:PARAMETER_NAME"`
+    DataType         string         `gorm:"column:DATA_TYPE"`
+    CharacterSetName sql.NullString `gorm:"column:CHARACTER_SET_NAME"`
+    CollationName    sql.NullString `gorm:"column:COLLATION_NAME"`
+    DtdIdentifier    string         `gorm:"column:DTD_IDENTIFIER"`
+    RoutineType      string         `gorm:"column:ROUTINE_TYPE"`
+}
+
+// StoredRoutine 存储过程或函数及其参数，Parameters 按 ORDINAL_POSITION 排序，不含函数的返回值。
+type StoredRoutine struct {
+    Routine    Routine
+    Parameters []Parameter
+}
+
+type Event struct {
+    EventCatalog        string         `gorm:"column:EVENT_CATALOG"`
+    EventSchema         string         `gorm:"column:EVENT_SCHEMA"`
+    EventName           string         `gorm:"column:EVENT_NAME"`
+    DEFINER             string         `gorm:"column:DEFINER"`
+    TimeZone            string         `gorm:"column:TIME_ZONE"`
+    EventBody           string         `gorm:"column:EVENT_BODY"`
+    EventDefinition     string         `gorm:"column:EVENT_DEFINITION"`
+    EventType           string         `gorm:"column:EVENT_TYPE"`
+    ExecuteAt           sql.NullTime   `gorm:"column:EXECUTE_AT"`
+    IntervalValue       sql.NullString `gorm:"column:INTERVAL_VALUE"`
+    IntervalField       sql.NullString `gorm:"column:INTERVAL_FIELD"`
+    SqlMode             string         `gorm:"column:SQL_MODE"`
+    STARTS              sql.NullTime   `gorm:"column:STARTS"`
+    ENDS                sql.NullTime   `gorm:"column:ENDS"`
+    STATUS              string         `gorm:"column:STATUS"`
+    OnCompletion        string         `gorm:"column:ON_COMPLETION"`
+    CREATED             sql.NullTime   `gorm:"column:CREATED"`
+    LastAltered         sql.NullTime   `gorm:"column:LAST_ALTERED"`
+    LastExecuted        sql.NullTime   `gorm:"column:LAST_EXECUTED"`
+    EventComment        string         `gorm:"column:EVENT_COMMENT"`
+    ORIGINATOR          int64          `gorm:"column:ORIGINATOR"`
+    CharacterSetClient  string         `gorm:"column:CHARACTER_SET_CLIENT"`
+    CollationConnection string         `gorm:"column:COLLATION_CONNECTION"`
+    DatabaseCollation   string         `gorm:"column:DATABASE_COLLATION"`
+}
+
+type Partition struct {
+    TableCatalog                string         `gorm:"column:TABLE_CATALOG"`
+    TableSchema                 string         `gorm:"column:TABLE_SCHEMA"`
+    TableName                   string         `gorm:"column:TABLE_NAME"`
+    PartitionName               sql.NullString `gorm:"column:PARTITION_NAME"`
+    SubpartitionName            sql.NullString `gorm:"column:SUBPARTITION_NAME"`
+    PartitionOrdinalPosition    sql.NullInt64  `gorm:"column:PARTITION_ORDINAL_POSITION"`
+    SubpartitionOrdinalPosition sql.NullInt64  `gorm:"column:SUBPARTITION_ORDINAL_POSITION"`
+    PartitionMethod             sql.NullString `gorm:"column:PARTITION_METHOD"`
+    SubpartitionMethod          sql.NullString `gorm:"column:SUBPARTITION_METHOD"`
+    PartitionExpression         sql.NullString `gorm:"column:PARTITION_EXPRESSION"`
+    SubpartitionExpression      sql.NullString `gorm:"column:SUBPARTITION_EXPRESSION"`
+    PartitionDescription        sql.NullString `gorm:"column:PARTITION_DESCRIPTION"`
+    TableRows                   sql.NullInt64  `gorm:"column:TABLE_ROWS"`
+    AvgRowLength                sql.NullInt64  `gorm:"column:AVG_ROW_LENGTH"`
+    DataLength                  sql.NullInt64  `gorm:"column:DATA_LENGTH"`
+    MaxDataLength               sql.NullInt64  `gorm:"column:MAX_DATA_LENGTH"`
+    IndexLength                 sql.NullInt64  `gorm:"column:INDEX_LENGTH"`
+    DataFree                    sql.NullInt64  `gorm:"column:DATA_FREE"`
+    CreateTime                  sql.NullTime   `gorm:"column:CREATE_TIME"`
+    UpdateTime                  sql.NullTime   `gorm:"column:UPDATE_TIME"`
+    CheckTime                   sql.NullTime   `gorm:"column:CHECK_TIME"`
+    CHECKSUM                    sql.NullInt64  `gorm:"column:CHECKSUM"`
+    PartitionComment            string         `gorm:"column:PARTITION_COMMENT"`
+    NODEGROUP                   sql.NullString `gorm:"column:NODEGROUP"`
+    TablespaceName              sql.NullString `gorm:"column:TABLESPACE_NAME"`
+}
@@ -0,0 +1,328 @@
+package mysqldiff
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "sort"
+    "strings"
+
+    "gorm.io/driver/mysql"
+    "gorm.io/gorm"
+    "gorm.io/gorm/logger"
+)
+
+const (
+    Dsn = "%s:%s@tcp(%s:%d)/information_schema?timeout=10s&parseTime=true&charset=%s"
+)
+
+// 差异的执行顺序：库、存储过程与函数、表与视图、删除触发器、新建触发器、事件、将库设为只读。
+//
+// 创建存储过程与函数时不检查其中引用的表，视图与触发器可能调用函数，因此在库之后最先执行。
+const (
+    ChangeOrderDatabase = iota
+    ChangeOrderRoutine
+    ChangeOrderTable
+    ChangeOrderDropTrigger
+    ChangeOrderTrigger
+    ChangeOrderEvent
+    ChangeOrderReadOnly
+)
+
+// AutoIncrement AUTO_INCREMENT 的比对方式。
+type AutoIncrement string
+
+const (
+    AutoIncrementNone   AutoIncrement = ""       // 不比对
+    AutoIncrementCreate AutoIncrement = "create" // 只在新建表时指定
+    AutoIncrementRaise  AutoIncrement = "raise"  // 新建表时指定，已有表的计数器小于源表时调大，不会调小
+)
+
+// 扩展表选项，ENCRYPTION、DATA DIRECTORY、TABLESPACE 与服务器环境相关，默认不比对。
+const (
+    TableOptionRowFormat        = "ROW_FORMAT"
+    TableOptionKeyBlockSize     = "KEY_BLOCK_SIZE"
+    TableOptionStatsPersistent  = "STATS_PERSISTENT"
+    TableOptionStatsAutoRecalc  = "STATS_AUTO_RECALC"
+    TableOptionStatsSamplePages = "STATS_SAMPLE_PAGES"
+    TableOptionCompression      = "COMPRESSION"
+    TableOptionEncryption       = "ENCRYPTION"
+    TableOptionPackKeys         = "PACK_KEYS"
+    TableOptionDataDirectory    = "DATA DIRECTORY"
+    TableOptionTablespace       = "TABLESPACE"
+)
+
+var (
+    // TableOptions 支持比对的扩展表选项。
+    TableOptions = []string{
+        TableOptionRowFormat, TableOptionKeyBlockSize,
+        TableOptionStatsPersistent, TableOptionStatsAutoRecalc, TableOptionStatsSamplePages,
+        TableOptionCompression, TableOptionEncryption, TableOptionPackKeys,
+        TableOptionDataDirectory, TableOptionTablespace,
+    }
+
+    // DefaultTableOptions 默认比对的扩展表选项。
+    DefaultTableOptions = []string{
+        TableOptionRowFormat, TableOptionKeyBlockSize,
+        TableOptionStatsPersistent, TableOptionStatsAutoRecalc, TableOptionStatsSamplePages,
+        TableOptionCompression, TableOptionPackKeys,
+    }
+)
+
+// Options 比对选项。
+type Options struct {
+    Comment        bool          // 是否比对注释
+    Foreign        bool          // 是否比对外键
+    Tidb           bool          // 是否 TiDB
+    Rename         bool          // 是否检测重命名
+    Database       bool          // 是否比对库的字符集、排序规则、默认加密与只读
+    CreateDatabase bool          // 目标数据库不存在时是否新建，仅 Diff 使用
+    AutoIncrement  AutoIncrement // AUTO_INCREMENT 的比对方式
+    TableOptions   []string      // 比对的扩展表选项，见 TableOptions
+    Hints          Hints
+}
+
+// Database 待比对的数据库，Db 需连接到 information_schema。
+type Database struct {
+    Db   *gorm.DB
+    Name string
+}
+
+// Result 比对结果，Changes 按对象名排序。
+type Result struct {
+    Schema  Schema
+    Options Options
+    Changes []Change
+
+    source *Catalog
+    target *Catalog
+}
+
+// Open 根据配置连接服务器的 information_schema。
+func Open(config DbConfig) (*gorm.DB, error) {
+    return gorm.Open(mysql.New(mysql.Config{
+        DSN: fmt.Sprintf(Dsn,
+            config.User, config.Password,
+            config.Host, config.Port,
+            config.Charset,
+        ),
+    }), &gorm.Config{
+        SkipDefaultTransaction: true,
+        DisableAutomaticPing:   true,
+        Logger:                 logger.Default.LogMode(logger.Silent),
+    })
+}
+
+// Diff 读取并比对源数据库与目标数据库，返回使目标数据库与源数据库一致所需的差异。
+func Diff(ctx context.Context, source Database, target Database, options Options) (*Result, error) {
+    sourceCatalog, err := Load(ctx, source.Db, source.Name)
+
+    if errors.Is(err, ErrSchemaNotFound) {
+        return nil, fmt.Errorf("源数据库 `%s` 不存在。", source.Name)
+    }
+
+    if err != nil {
+        return nil, err
+    }
+
+    if err := sourceCatalog.LoadStorage(source.Db, options.TableOptions); err != nil {
+        return nil, err
+    }
+
+    targetCatalog, err := Load(ctx, target.Db, target.Name)
+
+    if errors.Is(err, ErrSchemaNotFound) {
+        if !options.CreateDatabase {
+            return nil, fmt.Errorf("目标数据库 `%s` 不存在。", target.Name)
+        }
+
+        targetCatalog, err = MissingCatalog(target.Name), nil
+    }
+
+    if err != nil {
+        return nil, err
+    }
+
+    if err := targetCatalog.LoadStorage(target.Db, options.TableOptions); err != nil {
+        return nil, err
+    }
+
+    return Compare(sourceCatalog, targetCatalog, options), nil
+}
+
+// Compare 在内存中比对源数据库与目标数据库的结构。
+func Compare(source *Catalog, target *Catalog, options Options) *Result {
+    source.build()
+    target.build()
+
+    schema := source.Schema
+
+    // 回滚新建的库时，源库不存在，按目标库的字符集执行。
+    if source.missing {
+        schema = target.Schema
+    }
+
+    d := &differ{
+        options: options,
+        source:  source,
+        target:  target,
+        result:  &Result{Schema: schema, Options: options, source: source, target: target},
+    }
+
+    // RENAME TABLE ...
+    d.renameTables()
+
+    // DROP TABLE Or DROP VIEW...
+    d.drop()
+
+    for _, sourceTable := range source.Tables {
+        d.diff(sourceTable)
+    }
+
+    // DROP TRIGGER ... CREATE TRIGGER ...
+    d.diffTriggers()
+
+    // DROP PROCEDURE ... CREATE PROCEDURE ...
+    d.diffRoutines()
+
+    // DROP EVENT ... CREATE EVENT ... ALTER EVENT ...
+    d.diffEvents()
+
+    // CREATE DATABASE ... ALTER DATABASE ...
+    d.diffDatabase()
+
+    sort.SliceStable(d.result.Changes, func(i, j int) bool {
+        iOrder, jOrder := getChangeOrder(d.result.Changes[i]), getChangeOrder(d.result.Changes[j])
+
+        if iOrder != jOrder {
+            return iOrder < jOrder
+        }
+
+        // 新建的触发器保持 ACTION_ORDER 顺序。
+        if _, ok := d.result.Changes[i].(*TriggerChange); ok && iOrder == ChangeOrderTrigger {
+            return false
+        }
+
+        return d.result.Changes[i].ObjectName() < d.result.Changes[j].ObjectName()
+    })
+
+    return d.result
+}
+
+// Down 返回回滚差异：按目标数据库当前的结构，将已与源数据库一致的目标数据库恢复原状。
+//
+// 回滚只恢复结构，删除的列、表中的数据无法恢复。
+func (r *Result) Down() *Result {
+    if r.source == nil || r.target == nil {
+        return &Result{Schema: r.Schema, Options: r.Options}
+    }
+
+    options := r.Options
+    options.Hints = options.Hints.Reverse()
+
+    return Compare(r.target, r.source, options)
+}
+
+// Renderer 返回与比对选项一致的渲染器。
+func (r *Result) Renderer() Renderer {
+    return Renderer{Schema: r.Schema, Options: r.Options}
+}
+
+// Statements 返回按执行顺序排列的全部语句，含 SET NAMES 与 SET FOREIGN_KEY_CHECKS，无差异时返回空。
+func (r *Result) Statements() []string {
+    if len(r.Changes) <= 0 {
+        return nil
+    }
+
+    var (
+        statements []string
+        renderer   = r.Renderer()
+    )
+
+    statements = append(statements, fmt.Sprintf("SET NAMES %s;", r.Schema.DefaultCharacterSetName))
+    statements = append(statements, "SET FOREIGN_KEY_CHECKS=0;")
+
+    for _, change := range r.Changes {
+        statements = append(statements, renderer.Render(change)...)
+    }
+
+    statements = append(statements, "SET FOREIGN_KEY_CHECKS=1;")
+
+    return statements
+}
+
+// Script 返回完整的差异 SQL 脚本，无差异时返回空字符串。
+func (r *Result) Script() string {
+    if len(r.Changes) <= 0 {
+        return ""
+    }
+
+    var (
+        script   []string
+        renderer = r.Renderer()
+    )
+
+    script = append(script, fmt.Sprintf("SET NAMES %s;\n", r.Schema.DefaultCharacterSetName))
+    script = append(script, "SET FOREIGN_KEY_CHECKS=0;")
+    script = append(script, "")
+
+    for k, change := range r.Changes {
+        script = append(script, strings.Join(getScriptStatements(change, renderer.Render(change)), "\n"))
+
+        if k < len(r.Changes)-1 {
+            script = append(script, "")
+        }
+    }
+
+    script = append(script, "")
+    script = append(script, "SET FOREIGN_KEY_CHECKS=1;")
+
+    return strings.Join(script, "\n") + "\n"
+}
+
+// getChangeOrder 返回差异的执行顺序。
+func getChangeOrder(change Change) int {
+    switch c := change.(type) {
+    case *DatabaseChange:
+        if c.ReadOnly != nil && *c.ReadOnly {
+            return ChangeOrderReadOnly
+        }
+
+        return ChangeOrderDatabase
+    case *RoutineChange:
+        return ChangeOrderRoutine
+    case *EventChange:
+        return ChangeOrderEvent
+    case *TriggerChange:
+        if c.Type == ChangeDrop {
+            return ChangeOrderDropTrigger
+        }
+
+        return ChangeOrderTrigger
+    }
+
+    return ChangeOrderTable
+}
+
+// getScriptStatements 为触发器、存储过程、事件等包含语句块的 CREATE 语句（以及 ALTER EVENT）加上 DELIMITER，以便 mysql 客户端执行。
+func getScriptStatements(change Change, statements []string) []string {
+    _, isEvent := change.(*EventChange)
+
+    switch change.(type) {
+    case *TriggerChange, *RoutineChange, *EventChange:
+    default:
+        return statements
+    }
+
+    var scriptStatements []string
+
+    for _, statement := range statements {
+        if strings.HasPrefix(statement, "CREATE ") || isEvent && strings.HasPrefix(statement, "ALTER ") {
+            scriptStatements = append(scriptStatements, "DELIMITER ;;", strings.TrimSuffix(statement, ";")+";;", "DELIMITER ;")
+        } else {
+            scriptStatements = append(scriptStatements, statement)
+        }
+    }
+
+    return scriptStatements
+}
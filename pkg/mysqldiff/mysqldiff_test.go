@@ -0,0 +1,65 @@
+package mysqldiff
+
+import (
+    "strings"
+    "testing"
+)
+
+// mustParseDDL 解析 DDL，失败时终止测试。
+func mustParseDDL(t *testing.T, ddl string) *Catalog {
+    t.Helper()
+
+    catalog, err := ParseDDL("db", ddl)
+
+    if err != nil {
+        t.Fatalf("ParseDDL: %v", err)
+    }
+
+    return catalog
+}
+
+// getSpecKinds 返回全部表差异的类型。
+func getSpecKinds(result *Result) []string {
+    var kinds []string
+
+    for _, change := range result.Changes {
+        if c, ok := change.(*TableChange); ok {
+            for _, spec := range c.Specs {
+                kinds = append(kinds, spec.Kind())
+            }
+        }
+    }
+
+    return kinds
+}
+
+// getObjectChanges 返回触发器、存储过程与函数、事件差异的类型与对象名。
+func getObjectChanges(result *Result) []string {
+    var changes []string
+
+    for _, change := range result.Changes {
+        switch c := change.(type) {
+        case *TriggerChange:
+            changes = append(changes, string(c.Type)+" "+c.Name)
+        case *RoutineChange:
+            changes = append(changes, string(c.Type)+" "+c.RoutineType+" "+c.Name)
+        case *EventChange:
+            changes = append(changes, string(c.Type)+" "+c.Name)
+        }
+    }
+
+    return changes
+}
+
+// assertScript 检查脚本包含 contains 中的全部语句。
+func assertScript(t *testing.T, result *Result, contains ...string) {
+    t.Helper()
+
+    script := result.Script()
+
+    for _, s := range contains {
+        if !strings.Contains(script, s) {
+            t.Errorf("script does not contain %q:\n%s", s, script)
+        }
+    }
+}
@@ -0,0 +1,2167 @@
+package mysqldiff
+
+import (
+    "database/sql"
+    "fmt"
+    "io/fs"
+    "os"
+    "path/filepath"
+    "regexp"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/pingcap/tidb/pkg/parser"
+    "github.com/pingcap/tidb/pkg/parser/ast"
+    "github.com/pingcap/tidb/pkg/parser/charset"
+    "github.com/pingcap/tidb/pkg/parser/format"
+    "github.com/pingcap/tidb/pkg/parser/mysql"
+    "github.com/pingcap/tidb/pkg/parser/opcode"
+    "github.com/pingcap/tidb/pkg/parser/test_driver"
+    "github.com/pingcap/tidb/pkg/parser/types"
+    "github.com/samber/lo"
+)
+
+const (
+    DefaultCharset = "utf8mb4"
+    DefaultEngine  = "InnoDB"
+)
+
+var triggerPattern = regexp.MustCompile("(?is)^CREATE\\s+(?:DEFINER\\s*=\\s*(" + definerPattern + ")\\s+)?TRIGGER\\s+(?:IF\\s+NOT\\s+EXISTS\\s+)?(" + identifierPattern + ")\\s+(BEFORE|AFTER)\\s+(INSERT|UPDATE|DELETE)\\s+ON\\s+(" + identifierPattern + ")\\s+FOR\\s+EACH\\s+ROW\\s+(?:(FOLLOWS|PRECEDES)\\s+(" + identifierPattern + ")\\s+)?(.*?)\\s*$")
+
+var (
+    routinePattern = regexp.MustCompile("(?is)^CREATE\\s+(?:DEFINER\\s*=\\s*(" + definerPattern + ")\\s+)?(PROCEDURE|FUNCTION)\\s+(?:IF\\s+NOT\\s+EXISTS\\s+)?(" + identifierPattern + ")\\s*\\(")
+
+    returnsPattern = regexp.MustCompile("(?i)^RETURNS\\s")
+
+    // 函数返回值类型之后的修饰。
+    returnsModifierPattern = regexp.MustCompile("(?i)^\\s+(?:UNSIGNED|SIGNED|ZEROFILL|BINARY|(?:CHARSET|CHARACTER\\s+SET|COLLATE)\\s+\\w+)\\b")
+
+    routineCharacteristicPatterns = []*regexp.Regexp{
+        regexp.MustCompile("(?is)^COMMENT\\s+'((?:[^'\\\\]|\\\\.|'')*)'"),
+        regexp.MustCompile("(?i)^LANGUAGE\\s+SQL\\b"),
+        regexp.MustCompile("(?i)^(NOT\\s+)?DETERMINISTIC\\b"),
+        regexp.MustCompile("(?i)^(CONTAINS\\s+SQL|NO\\s+SQL|READS\\s+SQL\\s+DATA|MODIFIES\\s+SQL\\s+DATA)\\b"),
+        regexp.MustCompile("(?i)^SQL\\s+SECURITY\\s+(DEFINER|INVOKER)\\b"),
+    }
+
+    charsetKeywordPattern = regexp.MustCompile("\\b(charset|collate)\\b")
+    eventPattern          = regexp.MustCompile("(?is)^CREATE\\s+(?:DEFINER\\s*=\\s*(" + definerPattern + ")\\s+)?EVENT\\s+(?:IF\\s+NOT\\s+EXISTS\\s+)?(" + identifierPattern + ")\\s+ON\\s+SCHEDULE\\s+(.*?)\\s+((?:ON\\s+COMPLETION|ENABLE|DISABLE|COMMENT|DO)\\b.*)$")
+    eventAtPattern        = regexp.MustCompile("(?is)^AT\\s+(.+)$")
+    eventEveryPattern     = regexp.MustCompile("(?is)^EVERY\\s+('[^']*'|\\S+)\\s+(\\w+)(?:\\s+STARTS\\s+(.+?))?(?:\\s+ENDS\\s+(.+?))?$")
+    eventOptionPatterns   = []*regexp.Regexp{
+        regexp.MustCompile("(?i)^ON\\s+COMPLETION\\s+(NOT\\s+)?PRESERVE\\b"),
+        regexp.MustCompile("(?i)^(ENABLE|DISABLE\\s+ON\\s+(?:SLAVE|REPLICA)|DISABLE)\\b"),
+        regexp.MustCompile("(?is)^COMMENT\\s+'((?:[^'\\\\]|\\\\.|'')*)'"),
+        regexp.MustCompile("(?is)^DO\\s+(.*?)\\s*$"),
+    }
+    integerDisplayWidthPattern = regexp.MustCompile("^(tinyint|smallint|mediumint|int|bigint)\\(\\d+\\)")
+
+    createTablePattern   = regexp.MustCompile("(?is)^CREATE\\s+(?:TEMPORARY\\s+)?TABLE\\s+(?:IF\\s+NOT\\s+EXISTS\\s+)?" + identifierPattern + "\\s*\\(")
+    spatialIndexPattern  = regexp.MustCompile("(?is)^SPATIAL\\s+((?:KEY|INDEX)\\b.*)$")
+    spatialColumnPattern = regexp.MustCompile("(?is)^(`[^`]+`|\\w+)\\s+(GEOMETRY|POINT|LINESTRING|POLYGON|MULTIPOINT|MULTILINESTRING|MULTIPOLYGON|GEOMETRYCOLLECTION|GEOMCOLLECTION)\\b(.*)$")
+    sridPattern          = regexp.MustCompile("(?is)\\s*(?:/\\*!\\d*\\s*)?\\bSRID\\s+(\\d+)(?:\\s*\\*/)?")
+
+    // visibilityPattern 解析器不支持列的 [IN]VISIBLE，匹配前先跳过字符串与反引号中的标识符。
+    columnNamePattern      = regexp.MustCompile("^\\s*(`[^`]+`|\\w+)")
+    indexDefinitionPattern = regexp.MustCompile("(?i)^\\s*(?:PRIMARY|UNIQUE|KEY|INDEX|FULLTEXT|SPATIAL|CONSTRAINT|FOREIGN|CHECK)\\b")
+    visibilityPattern      = regexp.MustCompile("(?i)'(?:[^'\\\\]|\\\\.|'')*'|`[^`]*`|\\s*(?:/\\*!\\d*\\s*)?\\b(?:IN)?VISIBLE\\b(?:\\s*\\*/)?")
+
+    // statsPersistentPattern 解析器不保留 STATS_PERSISTENT 与 PACK_KEYS 的值，匹配前先跳过字符串。
+    statsPersistentPattern = regexp.MustCompile("(?i)'(?:[^'\\\\]|\\\\.|'')*'|\\b(STATS_PERSISTENT|PACK_KEYS)\\s*(?:=\\s*)?(0|1|DEFAULT)\\b")
+)
+
+// rowFormats 解析器中 ROW_FORMAT 的取值。
+var rowFormats = map[uint64]string{
+    ast.RowFormatDynamic:    "DYNAMIC",
+    ast.RowFormatFixed:      "FIXED",
+    ast.RowFormatCompressed: "COMPRESSED",
+    ast.RowFormatRedundant:  "REDUNDANT",
+    ast.RowFormatCompact:    "COMPACT",
+}
+
+const (
+    identifierPattern = "(?:`[^`]+`|\\w+)(?:\\.(?:`[^`]+`|\\w+))?"
+    definerPattern    = "(?:`[^`]+`|'[^']+'|\"[^\"]+\"|[\\w.%-]+)(?:@(?:`[^`]+`|'[^']+'|\"[^\"]+\"|[\\w.%-]+))?|CURRENT_USER(?:\\(\\))?"
+)
+
+// defaultCollations MySQL 8.0 各字符集的默认排序规则。
+var defaultCollations = map[string]string{
+    "utf8mb4": "utf8mb4_0900_ai_ci",
+    "utf8mb3": "utf8mb3_general_ci",
+    "utf8":    "utf8_general_ci",
+    "latin1":  "latin1_swedish_ci",
+    "ascii":   "ascii_general_ci",
+    "binary":  "binary",
+    "gbk":     "gbk_chinese_ci",
+    "gb2312":  "gb2312_chinese_ci",
+    "gb18030": "gb18030_chinese_ci",
+    "big5":    "big5_chinese_ci",
+    "ucs2":    "ucs2_general_ci",
+    "utf16":   "utf16_general_ci",
+    "utf32":   "utf32_general_ci",
+}
+
+// ReadDDL 读取目录（递归）或单个文件中的 CREATE TABLE / CREATE VIEW 语句，name 为库名。
+func ReadDDL(path string, name string) (*Catalog, error) {
+    var files []string
+
+    err := filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+        if err != nil {
+            return err
+        }
+
+        if !entry.IsDir() && (file == path || strings.EqualFold(filepath.Ext(file), ".sql")) {
+            files = append(files, file)
+        }
+
+        return nil
+    })
+
+    if err != nil {
+        return nil, err
+    }
+
+    p := newDdlParser(name)
+
+    for _, file := range files {
+        content, err := os.ReadFile(file)
+
+        if err != nil {
+            return nil, err
+        }
+
+        if err := p.parse(string(content)); err != nil {
+            return nil, fmt.Errorf("解析 `%s` 失败：%w", file, err)
+        }
+    }
+
+    return p.catalog(), nil
+}
+
+// ParseDDL 解析 CREATE TABLE / CREATE VIEW 语句，name 为库名。
+func ParseDDL(name string, ddl string) (*Catalog, error) {
+    p := newDdlParser(name)
+
+    if err := p.parse(ddl); err != nil {
+        return nil, err
+    }
+
+    return p.catalog(), nil
+}
+
+// ddlParser 将 DDL 语句转换为与 information_schema 一致的结构。
+type ddlParser struct {
+    parser *parser.Parser
+    c      *Catalog
+
+    tables     []*ast.CreateTableStmt
+    views      []*ast.CreateViewStmt
+    triggers   []ddlTrigger
+    spatials   map[string]map[string]spatialColumn
+    invisibles map[string]map[string]bool
+    options    map[string][]string
+}
+
+// spatialColumn 空间列的类型与 SRID，解析器不支持空间类型，解析前改为 BLOB。
+type spatialColumn struct {
+    DataType string
+    SrsId    sql.NullInt64
+}
+
+func newDdlParser(name string) *ddlParser {
+    return &ddlParser{
+        parser:     parser.New(),
+        spatials:   make(map[string]map[string]spatialColumn),
+        invisibles: make(map[string]map[string]bool),
+        options:    make(map[string][]string),
+        c: &Catalog{
+            Schema: Schema{
+                CatalogName:             "def",
+                SchemaName:              name,
+                DefaultCharacterSetName: DefaultCharset,
+                DefaultCollationName:    getDefaultCollation(DefaultCharset),
+            },
+        },
+    }
+}
+
+func (p *ddlParser) parse(ddl string) error {
+    for _, statement := range splitStatements(ddl) {
+        // 解析器不支持 CREATE TRIGGER。
+        if matches := triggerPattern.FindStringSubmatch(statement.Text); matches != nil {
+            p.createTrigger(matches)
+
+            continue
+        }
+
+        // 解析器不支持 CREATE FUNCTION。
+        if matches := routinePattern.FindStringSubmatchIndex(statement.Text); matches != nil {
+            if err := p.createRoutine(statement.Text, matches); err != nil {
+                return fmt.Errorf("第 %d 行：%w", statement.Line, err)
+            }
+
+            continue
+        }
+
+        // 解析器不支持 CREATE EVENT。
+        if matches := eventPattern.FindStringSubmatch(statement.Text); matches != nil {
+            if err := p.createEvent(matches); err != nil {
+                return fmt.Errorf("第 %d 行：%w", statement.Line, err)
+            }
+
+            continue
+        }
+
+        // 解析器不支持空间类型与空间索引。
+        text, spatials := rewriteSpatial(statement.Text)
+
+        // 解析器不支持不可见列。
+        text, invisibles := rewriteInvisible(text)
+
+        stmts, _, err := p.parser.Parse(text, "", "")
+
+        if err != nil {
+            return fmt.Errorf("第 %d 行：%w", statement.Line, err)
+        }
+
+        for _, stmt := range stmts {
+            switch s := stmt.(type) {
+            case *ast.CreateDatabaseStmt:
+                p.createDatabase(s)
+            case *ast.CreateTableStmt:
+                p.tables = append(p.tables, s)
+                p.spatials[s.Table.Name.L] = spatials
+                p.invisibles[s.Table.Name.L] = invisibles
+                p.options[s.Table.Name.L] = getStatsPersistentOptions(text)
+            case *ast.CreateViewStmt:
+                p.views = append(p.views, s)
+            }
+        }
+    }
+
+    return nil
+}
+
+// createDatabase 读取库的默认字符集。
+func (p *ddlParser) createDatabase(s *ast.CreateDatabaseStmt) {
+    for _, option := range s.Options {
+        switch option.Tp {
+        case ast.DatabaseOptionCharset:
+            p.c.Schema.DefaultCharacterSetName = strings.ToLower(option.Value)
+            p.c.Schema.DefaultCollationName = getDefaultCollation(p.c.Schema.DefaultCharacterSetName)
+        case ast.DatabaseOptionCollate:
+            p.c.Schema.DefaultCollationName = strings.ToLower(option.Value)
+        case ast.DatabaseOptionEncryption:
+            p.c.Schema.DefaultEncryption = sql.NullString{String: lo.Ternary(strings.EqualFold(option.Value, "Y"), "YES", "NO"), Valid: true}
+        }
+    }
+}
+
+// catalog 在全部语句解析后生成数据库结构，库的字符集可能出现在任意文件中。
+func (p *ddlParser) catalog() *Catalog {
+    for _, s := range p.tables {
+        p.createTable(s)
+    }
+
+    for _, s := range p.views {
+        p.createView(s)
+    }
+
+    p.referencedConstraints()
+
+    p.triggerOrders()
+
+    sort.SliceStable(p.c.Events, func(i, j int) bool {
+        return p.c.Events[i].EventName < p.c.Events[j].EventName
+    })
+
+    p.c.build()
+
+    return p.c
+}
+
+// CREATE TABLE ...
+func (p *ddlParser) createTable(s *ast.CreateTableStmt) {
+    schemaName := p.c.Schema.SchemaName
+    tableName := s.Table.Name.O
+
+    table := Table{
+        TableCatalog: "def",
+        TableSchema:  schemaName,
+        TableName:    tableName,
+        TableType:    "BASE TABLE",
+        ENGINE:       sql.NullString{String: DefaultEngine, Valid: true},
+    }
+
+    tableCharset := ""
+    tableCollation := ""
+    createOptions := p.options[s.Table.Name.L]
+
+    for _, option := range s.Options {
+        switch option.Tp {
+        case ast.TableOptionEngine:
+            table.ENGINE.String = option.StrValue
+        case ast.TableOptionCharset:
+            tableCharset = strings.ToLower(option.StrValue)
+        case ast.TableOptionCollate:
+            tableCollation = strings.ToLower(option.StrValue)
+        case ast.TableOptionComment:
+            table.TableComment = option.StrValue
+        case ast.TableOptionAutoIncrement:
+            table.AutoIncrement = sql.NullInt64{Int64: int64(option.UintValue), Valid: true}
+        case ast.TableOptionRowFormat:
+            if rowFormat, ok := rowFormats[option.UintValue]; ok {
+                createOptions = append(createOptions, "row_format="+rowFormat)
+            }
+        case ast.TableOptionKeyBlockSize:
+            createOptions = append(createOptions, fmt.Sprintf("KEY_BLOCK_SIZE=%d", option.UintValue))
+        case ast.TableOptionStatsAutoRecalc, ast.TableOptionStatsSamplePages:
+            if !option.Default {
+                name := lo.Ternary(option.Tp == ast.TableOptionStatsAutoRecalc, "stats_auto_recalc", "stats_sample_pages")
+                createOptions = append(createOptions, fmt.Sprintf("%s=%d", name, option.UintValue))
+            }
+        case ast.TableOptionCompression:
+            createOptions = append(createOptions, fmt.Sprintf("COMPRESSION=\"%s\"", option.StrValue))
+        case ast.TableOptionEncryption:
+            createOptions = append(createOptions, fmt.Sprintf("ENCRYPTION='%s'", option.StrValue))
+        case ast.TableOptionDataDirectory:
+            table.DataDirectory = option.StrValue
+        case ast.TableOptionTablespace:
+            table.Tablespace = option.StrValue
+        }
+    }
+
+    tableCharset, tableCollation = getCharsetCollation(tableCharset, tableCollation, p.c.Schema.DefaultCharacterSetName, p.c.Schema.DefaultCollationName)
+    table.TableCollation = sql.NullString{String: tableCollation, Valid: true}
+
+    var (
+        columns     []Column
+        constraints []*ast.Constraint
+        primaryKeys = make(map[string]bool)
+    )
+
+    for _, constraint := range s.Constraints {
+        if constraint.Tp == ast.ConstraintPrimaryKey {
+            for _, key := range constraint.Keys {
+                if key.Column != nil {
+                    primaryKeys[key.Column.Name.L] = true
+                }
+            }
+        }
+    }
+
+    // COLUMNS ...
+    for i, columnDef := range s.Cols {
+        column := p.column(columnDef, table, tableCharset, tableCollation)
+        column.OrdinalPosition = i + 1
+
+        if spatial, ok := p.spatials[s.Table.Name.L][columnDef.Name.Name.L]; ok {
+            column.DataType = spatial.DataType
+            column.ColumnType = spatial.DataType
+            column.CharacterMaximumLength = sql.NullInt64{}
+            column.CharacterOctetLength = sql.NullInt64{}
+            column.SrsId = spatial.SrsId
+        }
+
+        if p.invisibles[s.Table.Name.L][columnDef.Name.Name.L] {
+            column.EXTRA = strings.TrimSpace(column.EXTRA + " INVISIBLE")
+        }
+
+        for _, option := range columnDef.Options {
+            switch option.Tp {
+            case ast.ColumnOptionPrimaryKey:
+                primaryKeys[columnDef.Name.Name.L] = true
+                constraints = append(constraints, &ast.Constraint{
+                    Tp:   ast.ConstraintPrimaryKey,
+                    Keys: []*ast.IndexPartSpecification{{Column: columnDef.Name, Length: types.UnspecifiedLength}},
+                })
+            case ast.ColumnOptionUniqKey:
+                constraints = append(constraints, &ast.Constraint{
+                    Tp:   ast.ConstraintUniq,
+                    Name: columnDef.Name.Name.O,
+                    Keys: []*ast.IndexPartSpecification{{Column: columnDef.Name, Length: types.UnspecifiedLength}},
+                })
+            case ast.ColumnOptionReference:
+                constraints = append(constraints, &ast.Constraint{
+                    Tp:    ast.ConstraintForeignKey,
+                    Keys:  []*ast.IndexPartSpecification{{Column: columnDef.Name, Length: types.UnspecifiedLength}},
+                    Refer: option.Refer,
+                })
+            case ast.ColumnOptionCheck:
+                constraints = append(constraints, &ast.Constraint{
+                    Tp:       ast.ConstraintCheck,
+                    Name:     option.ConstraintName,
+                    Expr:     option.Expr,
+                    Enforced: option.Enforced,
+                })
+            }
+        }
+
+        if primaryKeys[columnDef.Name.Name.L] {
+            column.IsNullable = "NO"
+        }
+
+        columns = append(columns, column)
+    }
+
+    constraints = append(constraints, s.Constraints...)
+
+    columnMap := make(map[string]*Column)
+
+    for i := range columns {
+        columnMap[strings.ToLower(columns[i].ColumnName)] = &columns[i]
+    }
+
+    // KEY ...
+    var (
+        statistics []Statistic
+        indexNames = make(map[string]bool)
+    )
+
+    for _, constraint := range constraints {
+        if constraint.Tp == ast.ConstraintForeignKey || constraint.Tp == ast.ConstraintCheck {
+            continue
+        }
+
+        indexName := constraint.Name
+        nonUnique := int64(1)
+        indexType := getDefaultIndexType(table.ENGINE.String)
+        indexComment := ""
+        indexParser := ""
+        indexVisible := "YES"
+
+        if constraint.Option != nil {
+            indexComment = constraint.Option.Comment
+
+            if constraint.Option.Visibility == ast.IndexVisibilityInvisible {
+                indexVisible = "NO"
+            }
+
+            switch constraint.Option.Tp {
+            case ast.IndexTypeBtree:
+                indexType = "BTREE"
+            case ast.IndexTypeHash:
+                // InnoDB 不支持 HASH 索引，会忽略 USING HASH。
+                if getDefaultIndexType(table.ENGINE.String) == "HASH" {
+                    indexType = "HASH"
+                }
+            case ast.IndexTypeRtree:
+                indexType = "SPATIAL"
+            }
+        }
+
+        switch constraint.Tp {
+        case ast.ConstraintPrimaryKey:
+            indexName = "PRIMARY"
+            nonUnique = 0
+        case ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex:
+            nonUnique = 0
+        case ast.ConstraintFulltext:
+            indexType = "FULLTEXT"
+
+            if constraint.Option != nil {
+                indexParser = constraint.Option.ParserName.L
+            }
+        }
+
+        if indexName == "" {
+            indexName = getIndexName(constraint.Keys, indexNames)
+        }
+
+        indexNames[strings.ToLower(indexName)] = true
+
+        for seq, key := range constraint.Keys {
+            statistic := Statistic{
+                TableCatalog: "def",
+                TableSchema:  schemaName,
+                TableName:    tableName,
+                NonUnique:    nonUnique,
+                IndexSchema:  schemaName,
+                IndexName:    indexName,
+                SeqInIndex:   seq + 1,
+                COLLATION:    sql.NullString{String: "A", Valid: true},
+                IndexType:    indexType,
+                IndexComment: indexComment,
+                IsVisible:    sql.NullString{String: indexVisible, Valid: true},
+                Parser:       indexParser,
+            }
+
+            if key.Column != nil {
+                statistic.ColumnName = key.Column.Name.O
+
+                if column, ok := columnMap[key.Column.Name.L]; ok {
+                    statistic.ColumnName = column.ColumnName
+
+                    if column.IsNullable == "YES" {
+                        statistic.NULLABLE = "YES"
+                    }
+
+                    if seq == 0 && column.ColumnKey == "" {
+                        switch {
+                        case indexName == "PRIMARY":
+                            column.ColumnKey = "PRI"
+                        case nonUnique == 0 && len(constraint.Keys) == 1:
+                            column.ColumnKey = "UNI"
+                        default:
+                            column.ColumnKey = "MUL"
+                        }
+                    }
+                }
+            }
+
+            if key.Expr != nil {
+                statistic.EXPRESSION = sql.NullString{String: restoreSchemaExpr(key.Expr), Valid: true}
+            }
+
+            if key.Desc {
+                statistic.COLLATION = sql.NullString{String: "D", Valid: true}
+            }
+
+            if key.Length > 0 {
+                statistic.SubPart = sql.NullInt32{Int32: int32(key.Length), Valid: true}
+            }
+
+            if indexType == "FULLTEXT" {
+                statistic.COLLATION = sql.NullString{}
+            }
+
+            statistics = append(statistics, statistic)
+        }
+    }
+
+    // CONSTRAINT [symbol] FOREIGN KEY ...
+    for _, constraint := range constraints {
+        if constraint.Tp != ast.ConstraintForeignKey || constraint.Refer == nil {
+            continue
+        }
+
+        constraintName := constraint.Name
+
+        if constraintName == "" {
+            constraintName = fmt.Sprintf("%s_ibfk_%d", tableName, len(p.foreignKeyNames(tableName))+1)
+        }
+
+        p.c.TableConstraints = append(p.c.TableConstraints, TableConstraints{
+            ConstraintCatalog: "def",
+            ConstraintSchema:  schemaName,
+            ConstraintName:    constraintName,
+            TableSchema:       schemaName,
+            TableName:         tableName,
+            ConstraintType:    "FOREIGN KEY",
+        })
+
+        referential := ReferentialConstraints{
+            ConstraintCatalog:       "def",
+            ConstraintSchema:        schemaName,
+            ConstraintName:          constraintName,
+            UniqueConstraintCatalog: "def",
+            UniqueConstraintSchema:  schemaName,
+            MatchOption:             "NONE",
+            UpdateRule:              "NO ACTION",
+            DeleteRule:              "NO ACTION",
+            TableName:               tableName,
+            ReferencedTableName:     constraint.Refer.Table.Name.O,
+        }
+
+        if constraint.Refer.OnDelete != nil && constraint.Refer.OnDelete.ReferOpt != ast.ReferOptionNoOption {
+            referential.DeleteRule = constraint.Refer.OnDelete.ReferOpt.String()
+        }
+
+        if constraint.Refer.OnUpdate != nil && constraint.Refer.OnUpdate.ReferOpt != ast.ReferOptionNoOption {
+            referential.UpdateRule = constraint.Refer.OnUpdate.ReferOpt.String()
+        }
+
+        p.c.ReferentialConstraints = append(p.c.ReferentialConstraints, referential)
+
+        var columnNames []string
+
+        for i, key := range constraint.Keys {
+            if key.Column == nil || i >= len(constraint.Refer.IndexPartSpecifications) {
+                continue
+            }
+
+            columnNames = append(columnNames, key.Column.Name.L)
+
+            p.c.KeyColumnUsages = append(p.c.KeyColumnUsages, KeyColumnUsage{
+                ConstraintCatalog:          "def",
+                ConstraintSchema:           schemaName,
+                ConstraintName:             constraintName,
+                TableCatalog:               "def",
+                TableSchema:                schemaName,
+                TableName:                  tableName,
+                ColumnName:                 key.Column.Name.O,
+                OrdinalPosition:            int64(i + 1),
+                PositionInUniqueConstraint: int64(i + 1),
+                ReferencedTableSchema:      schemaName,
+                ReferencedTableName:        constraint.Refer.Table.Name.O,
+                ReferencedColumnName:       constraint.Refer.IndexPartSpecifications[i].Column.Name.O,
+            })
+        }
+
+        // 外键列没有可用索引时，MySQL 会自动创建与约束同名的索引。
+        if !hasLeadingIndex(statistics, columnNames) {
+            indexName := constraint.Name
+
+            if indexName == "" {
+                indexName = getIndexName(constraint.Keys, indexNames)
+            }
+
+            indexNames[strings.ToLower(indexName)] = true
+
+            for seq, columnName := range columnNames {
+                statistic := Statistic{
+                    TableCatalog: "def",
+                    TableSchema:  schemaName,
+                    TableName:    tableName,
+                    NonUnique:    1,
+                    IndexSchema:  schemaName,
+                    IndexName:    indexName,
+                    SeqInIndex:   seq + 1,
+                    ColumnName:   columnName,
+                    COLLATION:    sql.NullString{String: "A", Valid: true},
+                    IndexType:    "BTREE",
+                    IsVisible:    sql.NullString{String: "YES", Valid: true},
+                }
+
+                if column, ok := columnMap[columnName]; ok {
+                    statistic.ColumnName = column.ColumnName
+
+                    if column.IsNullable == "YES" {
+                        statistic.NULLABLE = "YES"
+                    }
+                }
+
+                statistics = append(statistics, statistic)
+            }
+        }
+    }
+
+    // CONSTRAINT [symbol] CHECK (expr) [NOT] ENFORCED，未命名的约束按顺序命名为 <表名>_chk_<n>。
+    checkNumber := 0
+
+    for _, constraint := range constraints {
+        if constraint.Tp != ast.ConstraintCheck {
+            continue
+        }
+
+        constraintName := constraint.Name
+
+        if constraintName == "" {
+            checkNumber++
+            constraintName = fmt.Sprintf("%s_chk_%d", tableName, checkNumber)
+        }
+
+        // CHECK_CLAUSE 含最外层的括号。
+        checkClause := restoreSchemaExpr(constraint.Expr)
+
+        if !strings.HasPrefix(checkClause, "(") || getClosingParen(checkClause, 1) != len(checkClause)-1 {
+            checkClause = "(" + checkClause + ")"
+        }
+
+        p.c.TableConstraints = append(p.c.TableConstraints, TableConstraints{
+            ConstraintCatalog: "def",
+            ConstraintSchema:  schemaName,
+            ConstraintName:    constraintName,
+            TableSchema:       schemaName,
+            TableName:         tableName,
+            ConstraintType:    "CHECK",
+            ENFORCED:          lo.Ternary(constraint.Enforced, "YES", "NO"),
+        })
+
+        p.c.CheckConstraints = append(p.c.CheckConstraints, CheckConstraints{
+            ConstraintCatalog: "def",
+            ConstraintSchema:  schemaName,
+            ConstraintName:    constraintName,
+            CheckClause:       checkClause,
+        })
+    }
+
+    // PARTITION BY ...
+    if s.Partition != nil {
+        createOptions = append(createOptions, "partitioned")
+    }
+
+    if len(createOptions) > 0 {
+        table.CreateOptions = sql.NullString{String: strings.Join(createOptions, " "), Valid: true}
+    }
+
+    if s.Partition != nil {
+        p.c.Partitions = append(p.c.Partitions, p.partitions(s.Partition, table)...)
+    }
+
+    p.c.Tables = append(p.c.Tables, table)
+    p.c.Columns = append(p.c.Columns, columns...)
+    p.c.Statistics = append(p.c.Statistics, statistics...)
+}
+
+// partitions 按 information_schema.PARTITIONS 的格式返回分区，有子分区时每个子分区一行。
+func (p *ddlParser) partitions(options *ast.PartitionOptions, table Table) []Partition {
+    var partitions []Partition
+
+    method, expression := getPartitionMethod(&options.PartitionMethod)
+    count := len(options.Definitions)
+
+    if count <= 0 {
+        count = int(max(options.Num, 1))
+    }
+
+    for i := 0; i < count; i++ {
+        partition := Partition{
+            TableCatalog:             table.TableCatalog,
+            TableSchema:              table.TableSchema,
+            TableName:                table.TableName,
+            PartitionName:            sql.NullString{String: fmt.Sprintf("p%d", i), Valid: true},
+            PartitionOrdinalPosition: sql.NullInt64{Int64: int64(i + 1), Valid: true},
+            PartitionMethod:          sql.NullString{String: method, Valid: true},
+            PartitionExpression:      sql.NullString{String: expression, Valid: true},
+        }
+
+        var subpartitionNames []string
+
+        if i < len(options.Definitions) {
+            definition := options.Definitions[i]
+            partition.PartitionName.String = definition.Name.O
+
+            if description := getPartitionDescription(definition.Clause); description != "" {
+                partition.PartitionDescription = sql.NullString{String: description, Valid: true}
+            }
+
+            if comment, ok := definition.Comment(); ok {
+                partition.PartitionComment = comment
+            }
+
+            for _, sub := range definition.Sub {
+                subpartitionNames = append(subpartitionNames, sub.Name.O)
+            }
+        }
+
+        if options.Sub == nil {
+            partitions = append(partitions, partition)
+
+            continue
+        }
+
+        subMethod, subExpression := getPartitionMethod(options.Sub)
+        partition.SubpartitionMethod = sql.NullString{String: subMethod, Valid: true}
+        partition.SubpartitionExpression = sql.NullString{String: subExpression, Valid: true}
+
+        if len(subpartitionNames) <= 0 {
+            for j := 0; j < int(max(options.Sub.Num, 1)); j++ {
+                subpartitionNames = append(subpartitionNames, fmt.Sprintf("%ssp%d", partition.PartitionName.String, j))
+            }
+        }
+
+        for j, name := range subpartitionNames {
+            subpartition := partition
+            subpartition.SubpartitionName = sql.NullString{String: name, Valid: true}
+            subpartition.SubpartitionOrdinalPosition = sql.NullInt64{Int64: int64(j + 1), Valid: true}
+
+            partitions = append(partitions, subpartition)
+        }
+    }
+
+    return partitions
+}
+
+// column 将列定义转换为 information_schema.COLUMNS 中的值。
+func (p *ddlParser) column(columnDef *ast.ColumnDef, table Table, tableCharset string, tableCollation string) Column {
+    ft := columnDef.Tp
+
+    column := Column{
+        TableCatalog: "def",
+        TableSchema:  table.TableSchema,
+        TableName:    table.TableName,
+        ColumnName:   columnDef.Name.Name.O,
+        IsNullable:   "YES",
+        PRIVILEGES:   "select,insert,update,references",
+    }
+
+    columnCollation := ft.GetCollate()
+
+    for _, option := range columnDef.Options {
+        if option.Tp == ast.ColumnOptionCollate {
+            columnCollation = option.StrValue
+        }
+    }
+
+    column.DataType, column.ColumnType = getColumnType(ft)
+
+    // CHARACTER SET ...
+    if isCharsetType(ft) {
+        columnCharset := strings.ToLower(ft.GetCharset())
+        columnCollation = strings.ToLower(columnCollation)
+
+        if columnCharset == "" && columnCollation == "" {
+            columnCharset, columnCollation = tableCharset, tableCollation
+        } else {
+            columnCharset, columnCollation = getCharsetCollation(columnCharset, columnCollation, tableCharset, tableCollation)
+        }
+
+        column.CharacterSetName = sql.NullString{String: columnCharset, Valid: true}
+        column.CollationName = sql.NullString{String: columnCollation, Valid: true}
+
+        maxLength := getCharacterMaximumLength(ft)
+        maxLen := int64(1)
+
+        if info, err := charset.GetCharsetInfo(columnCharset); err == nil {
+            maxLen = int64(info.Maxlen)
+        }
+
+        column.CharacterMaximumLength = sql.NullInt64{Int64: maxLength, Valid: true}
+        column.CharacterOctetLength = sql.NullInt64{Int64: maxLength * maxLen, Valid: true}
+
+        if types.IsTypeBlob(ft.GetType()) {
+            column.CharacterOctetLength = column.CharacterMaximumLength
+        }
+    } else if types.IsTypeBlob(ft.GetType()) || ft.GetType() == mysql.TypeString || ft.GetType() == mysql.TypeVarchar || ft.GetType() == mysql.TypeVarString {
+        maxLength := getCharacterMaximumLength(ft)
+
+        column.CharacterMaximumLength = sql.NullInt64{Int64: maxLength, Valid: true}
+        column.CharacterOctetLength = sql.NullInt64{Int64: maxLength, Valid: true}
+    }
+
+    // NUMERIC ...
+    switch ft.GetType() {
+    case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
+        column.NumericPrecision = sql.NullInt64{Int64: int64(getIntegerPrecision(ft.GetType(), mysql.HasUnsignedFlag(ft.GetFlag()))), Valid: true}
+        column.NumericScale = sql.NullInt64{Int64: 0, Valid: true}
+    case mysql.TypeNewDecimal:
+        precision, scale := ft.GetFlen(), ft.GetDecimal()
+
+        if precision == types.UnspecifiedLength {
+            precision = 10
+        }
+
+        if scale == types.UnspecifiedLength {
+            scale = 0
+        }
+
+        column.NumericPrecision = sql.NullInt64{Int64: int64(precision), Valid: true}
+        column.NumericScale = sql.NullInt64{Int64: int64(scale), Valid: true}
+    case mysql.TypeFloat, mysql.TypeDouble:
+        precision := 12
+
+        if ft.GetType() == mysql.TypeDouble {
+            precision = 22
+        }
+
+        if ft.GetFlen() != types.UnspecifiedLength {
+            precision = ft.GetFlen()
+        }
+
+        column.NumericPrecision = sql.NullInt64{Int64: int64(precision), Valid: true}
+
+        if ft.GetDecimal() != types.UnspecifiedLength {
+            column.NumericScale = sql.NullInt64{Int64: int64(ft.GetDecimal()), Valid: true}
+        }
+    case mysql.TypeBit:
+        precision := ft.GetFlen()
+
+        if precision == types.UnspecifiedLength {
+            precision = 1
+        }
+
+        column.NumericPrecision = sql.NullInt64{Int64: int64(precision), Valid: true}
+    case mysql.TypeDatetime, mysql.TypeTimestamp, mysql.TypeDuration:
+        precision := ft.GetDecimal()
+
+        if precision == types.UnspecifiedLength {
+            precision = 0
+        }
+
+        column.DatetimePrecision = sql.NullInt64{Int64: int64(precision), Valid: true}
+    }
+
+    var extra []string
+
+    for _, option := range columnDef.Options {
+        switch option.Tp {
+        case ast.ColumnOptionNotNull:
+            column.IsNullable = "NO"
+        case ast.ColumnOptionNull:
+            column.IsNullable = "YES"
+        case ast.ColumnOptionAutoIncrement:
+            extra = append(extra, "auto_increment")
+        case ast.ColumnOptionDefaultValue:
+            var generated bool
+
+            column.ColumnDefault, generated = getDefaultValue(option.Expr, column)
+
+            // TEXT、JSON 等类型的默认值只能是表达式，('abc') 会被解析为字面量。
+            if !generated && column.ColumnDefault.Valid && isExpressionDefaultType(column.DataType) {
+                column.ColumnDefault.String = restoreSchemaExpr(option.Expr)
+                generated = true
+            }
+
+            if generated {
+                extra = append([]string{"DEFAULT_GENERATED"}, extra...)
+            }
+        case ast.ColumnOptionOnUpdate:
+            extra = append(extra, fmt.Sprintf("on update %s", getCurrentTimestamp(option.Expr)))
+        case ast.ColumnOptionComment:
+            if value, ok := option.Expr.(*test_driver.ValueExpr); ok {
+                column.ColumnComment = value.GetString()
+            }
+        case ast.ColumnOptionGenerated:
+            column.GenerationExpression = restoreSchemaExpr(option.Expr)
+
+            if option.Stored {
+                extra = append(extra, "STORED GENERATED")
+            } else {
+                extra = append(extra, "VIRTUAL GENERATED")
+            }
+        }
+    }
+
+    column.EXTRA = strings.Join(extra, " ")
+
+    return column
+}
+
+// CREATE VIEW ...
+func (p *ddlParser) createView(s *ast.CreateViewStmt) {
+    viewName := s.ViewName.Name.O
+
+    view := View{
+        TableCatalog:        "def",
+        TableSchema:         p.c.Schema.SchemaName,
+        TableName:           viewName,
+        ViewDefinition:      s.Select.Text(),
+        CheckOption:         "NONE",
+        IsUpdatable:         "YES",
+        SecurityType:        "DEFINER",
+        CharacterSetClient:  p.c.Schema.DefaultCharacterSetName,
+        CollationConnection: p.c.Schema.DefaultCollationName,
+    }
+
+    if s.Security == ast.SecurityInvoker {
+        view.SecurityType = "INVOKER"
+    }
+
+    if s.Definer != nil && !s.Definer.CurrentUser {
+        view.DEFINER = fmt.Sprintf("%s@%s", s.Definer.Username, s.Definer.Hostname)
+    }
+
+    p.c.Tables = append(p.c.Tables, Table{
+        TableCatalog: "def",
+        TableSchema:  p.c.Schema.SchemaName,
+        TableName:    viewName,
+        TableType:    "VIEW",
+        TableComment: "VIEW",
+    })
+    p.c.Views = append(p.c.Views, view)
+}
+
+// referencedConstraints 补全外键引用的唯一索引名。
+func (p *ddlParser) referencedConstraints() {
+    uniqueIndexes := make(map[string]map[string]string)
+
+    for _, statistic := range p.c.Statistics {
+        if statistic.NonUnique != 0 {
+            continue
+        }
+
+        if _, ok := uniqueIndexes[statistic.TableName]; !ok {
+            uniqueIndexes[statistic.TableName] = make(map[string]string)
+        }
+
+        uniqueIndexes[statistic.TableName][statistic.IndexName] += strings.ToLower(statistic.ColumnName) + ","
+    }
+
+    for i, referential := range p.c.ReferentialConstraints {
+        var columnNames string
+
+        for _, keyColumnUsage := range p.c.KeyColumnUsages {
+            if keyColumnUsage.TableName == referential.TableName && keyColumnUsage.ConstraintName == referential.ConstraintName {
+                columnNames += strings.ToLower(keyColumnUsage.ReferencedColumnName) + ","
+            }
+        }
+
+        if uniqueIndexes[referential.ReferencedTableName]["PRIMARY"] == columnNames {
+            p.c.ReferentialConstraints[i].UniqueConstraintName = "PRIMARY"
+
+            continue
+        }
+
+        for indexName, indexColumnNames := range uniqueIndexes[referential.ReferencedTableName] {
+            if indexColumnNames == columnNames {
+                p.c.ReferentialConstraints[i].UniqueConstraintName = indexName
+            }
+        }
+    }
+}
+
+func (p *ddlParser) foreignKeyNames(tableName string) []string {
+    var names []string
+
+    for _, tableConstraint := range p.c.TableConstraints {
+        if tableConstraint.TableName == tableName && tableConstraint.ConstraintType == "FOREIGN KEY" {
+            names = append(names, tableConstraint.ConstraintName)
+        }
+    }
+
+    return names
+}
+
+// getColumnType 返回 DATA_TYPE 与 COLUMN_TYPE，未指定长度的整数类型不带显示宽度（与 MySQL 8.0 一致）。
+func getColumnType(ft *types.FieldType) (string, string) {
+    dataType := strings.ToLower(types.TypeToStr(ft.GetType(), ft.GetCharset()))
+    columnType := dataType
+    flen, decimal := ft.GetFlen(), ft.GetDecimal()
+
+    switch ft.GetType() {
+    case mysql.TypeEnum, mysql.TypeSet:
+        var elems []string
+
+        for _, elem := range ft.GetElems() {
+            elems = append(elems, fmt.Sprintf("'%s'", strings.ReplaceAll(elem, "'", "''")))
+        }
+
+        columnType = fmt.Sprintf("%s(%s)", dataType, strings.Join(elems, ","))
+    case mysql.TypeNewDecimal:
+        if flen == types.UnspecifiedLength {
+            flen = 10
+        }
+
+        if decimal == types.UnspecifiedLength {
+            decimal = 0
+        }
+
+        columnType = fmt.Sprintf("%s(%d,%d)", dataType, flen, decimal)
+    case mysql.TypeFloat, mysql.TypeDouble:
+        if flen != types.UnspecifiedLength && decimal != types.UnspecifiedLength {
+            columnType = fmt.Sprintf("%s(%d,%d)", dataType, flen, decimal)
+        }
+    case mysql.TypeDatetime, mysql.TypeTimestamp, mysql.TypeDuration:
+        if decimal > 0 {
+            columnType = fmt.Sprintf("%s(%d)", dataType, decimal)
+        }
+    case mysql.TypeString, mysql.TypeBit:
+        if flen == types.UnspecifiedLength {
+            flen = 1
+        }
+
+        columnType = fmt.Sprintf("%s(%d)", dataType, flen)
+    case mysql.TypeVarchar, mysql.TypeVarString, mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong, mysql.TypeYear:
+        if flen != types.UnspecifiedLength {
+            columnType = fmt.Sprintf("%s(%d)", dataType, flen)
+        }
+    }
+
+    if mysql.HasUnsignedFlag(ft.GetFlag()) || mysql.HasZerofillFlag(ft.GetFlag()) {
+        switch ft.GetType() {
+        case mysql.TypeBit, mysql.TypeYear:
+        default:
+            columnType += " unsigned"
+        }
+    }
+
+    if mysql.HasZerofillFlag(ft.GetFlag()) {
+        columnType += " zerofill"
+    }
+
+    return dataType, columnType
+}
+
+// isCharsetType 是否为带字符集的类型。
+func isCharsetType(ft *types.FieldType) bool {
+    if ft.GetCharset() == charset.CharsetBin {
+        return false
+    }
+
+    switch ft.GetType() {
+    case mysql.TypeString, mysql.TypeVarchar, mysql.TypeVarString, mysql.TypeEnum, mysql.TypeSet:
+        return true
+    }
+
+    return types.IsTypeBlob(ft.GetType())
+}
+
+// getCharacterMaximumLength 返回 CHARACTER_MAXIMUM_LENGTH。
+func getCharacterMaximumLength(ft *types.FieldType) int64 {
+    switch ft.GetType() {
+    case mysql.TypeTinyBlob:
+        return 255
+    case mysql.TypeBlob:
+        return 65535
+    case mysql.TypeMediumBlob:
+        return 16777215
+    case mysql.TypeLongBlob:
+        return 4294967295
+    case mysql.TypeEnum:
+        var maxLength int
+
+        for _, elem := range ft.GetElems() {
+            if len([]rune(elem)) > maxLength {
+                maxLength = len([]rune(elem))
+            }
+        }
+
+        return int64(maxLength)
+    case mysql.TypeSet:
+        var length int
+
+        for i, elem := range ft.GetElems() {
+            if i > 0 {
+                length++
+            }
+
+            length += len([]rune(elem))
+        }
+
+        return int64(length)
+    }
+
+    if ft.GetFlen() == types.UnspecifiedLength {
+        return 1
+    }
+
+    return int64(ft.GetFlen())
+}
+
+// getIntegerPrecision 返回整数类型的 NUMERIC_PRECISION。
+func getIntegerPrecision(tp byte, unsigned bool) int {
+    precisions := map[byte]int{
+        mysql.TypeTiny:     3,
+        mysql.TypeShort:    5,
+        mysql.TypeInt24:    7,
+        mysql.TypeLong:     10,
+        mysql.TypeLonglong: 19,
+    }
+
+    if unsigned && tp == mysql.TypeLonglong {
+        return 20
+    }
+
+    if unsigned && tp == mysql.TypeInt24 {
+        return 8
+    }
+
+    return precisions[tp]
+}
+
+// getCharsetCollation 补全字符集与排序规则，都未指定时使用上级的默认值。
+func getCharsetCollation(cs string, collation string, defaultCharset string, defaultCollation string) (string, string) {
+    switch {
+    case cs == "" && collation == "":
+        return defaultCharset, defaultCollation
+    case cs == "":
+        return strings.SplitN(collation, "_", 2)[0], collation
+    case collation == "":
+        return cs, getDefaultCollation(cs)
+    }
+
+    return cs, collation
+}
+
+func getDefaultCollation(cs string) string {
+    if collation, ok := defaultCollations[cs]; ok {
+        return collation
+    }
+
+    return cs + "_general_ci"
+}
+
+// getIndexName 未命名索引以第一列命名，重名时追加 _2、_3 ...
+func getIndexName(keys []*ast.IndexPartSpecification, indexNames map[string]bool) string {
+    name := "idx"
+
+    if len(keys) > 0 && keys[0].Column != nil {
+        name = keys[0].Column.Name.O
+    } else if len(keys) > 0 && keys[0].Expr != nil {
+        name = "functional_index"
+    }
+
+    indexName := name
+
+    for i := 2; indexNames[strings.ToLower(indexName)]; i++ {
+        indexName = fmt.Sprintf("%s_%d", name, i)
+    }
+
+    return indexName
+}
+
+// hasLeadingIndex 是否存在以 columnNames 开头的索引。
+func hasLeadingIndex(statistics []Statistic, columnNames []string) bool {
+    indexColumns := make(map[string][]string)
+
+    for _, statistic := range statistics {
+        indexColumns[statistic.IndexName] = append(indexColumns[statistic.IndexName], strings.ToLower(statistic.ColumnName))
+    }
+
+    for _, names := range indexColumns {
+        if len(names) >= len(columnNames) && strings.Join(names[:len(columnNames)], ",") == strings.Join(columnNames, ",") {
+            return true
+        }
+    }
+
+    return false
+}
+
+// getDefaultValue 返回 COLUMN_DEFAULT，generated 表示默认值为表达式。
+func getDefaultValue(expr ast.ExprNode, column Column) (sql.NullString, bool) {
+    switch e := expr.(type) {
+    case *test_driver.ValueExpr:
+        value, ok := getValue(e, column)
+
+        return sql.NullString{String: value, Valid: ok}, false
+    case *ast.FuncCallExpr:
+        if isCurrentTimestamp(e) {
+            return sql.NullString{String: getCurrentTimestamp(e), Valid: true}, true
+        }
+    case *ast.UnaryOperationExpr:
+        if value, ok := e.V.(*test_driver.ValueExpr); ok && e.Op == opcode.Minus {
+            v, _ := getValue(value, column)
+
+            return sql.NullString{String: "-" + v, Valid: true}, false
+        }
+    }
+
+    return sql.NullString{String: restoreSchemaExpr(expr), Valid: true}, true
+}
+
+// getValue 返回字面量的值，NULL 时 ok 为 false。
+func getValue(e *test_driver.ValueExpr, column Column) (string, bool) {
+    switch e.Kind() {
+    case test_driver.KindNull:
+        return "", false
+    case test_driver.KindInt64:
+        if e.Type.GetFlag()&mysql.IsBooleanFlag != 0 {
+            if e.GetInt64() > 0 {
+                return "1", true
+            }
+
+            return "0", true
+        }
+
+        return formatDecimal(strconv.FormatInt(e.GetInt64(), 10), column), true
+    case test_driver.KindUint64:
+        return formatDecimal(strconv.FormatUint(e.GetUint64(), 10), column), true
+    case test_driver.KindFloat32, test_driver.KindFloat64:
+        return formatDecimal(strconv.FormatFloat(e.GetFloat64(), 'f', -1, 64), column), true
+    case test_driver.KindMysqlDecimal:
+        return formatDecimal(e.GetMysqlDecimal().String(), column), true
+    case test_driver.KindBinaryLiteral:
+        if column.DataType == "bit" {
+            return e.GetBinaryLiteral().ToBitLiteralString(true), true
+        }
+
+        return string(e.GetBytes()), true
+    }
+
+    return e.GetString(), true
+}
+
+// formatDecimal 按 decimal 列的小数位数补齐默认值。
+func formatDecimal(value string, column Column) string {
+    if column.DataType != "decimal" || !column.NumericScale.Valid {
+        return value
+    }
+
+    parts := strings.SplitN(value, ".", 2)
+    scale := int(column.NumericScale.Int64)
+
+    if scale <= 0 {
+        return parts[0]
+    }
+
+    fraction := ""
+
+    if len(parts) > 1 {
+        fraction = parts[1]
+    }
+
+    if len(fraction) > scale {
+        fraction = fraction[:scale]
+    }
+
+    return parts[0] + "." + fraction + strings.Repeat("0", scale-len(fraction))
+}
+
+func isCurrentTimestamp(e *ast.FuncCallExpr) bool {
+    switch e.FnName.L {
+    case ast.CurrentTimestamp, ast.Now, ast.LocalTime, ast.LocalTimestamp:
+        return true
+    }
+
+    return false
+}
+
+// getCurrentTimestamp 返回 CURRENT_TIMESTAMP 或 CURRENT_TIMESTAMP(fsp)。
+func getCurrentTimestamp(expr ast.ExprNode) string {
+    if e, ok := expr.(*ast.FuncCallExpr); ok && isCurrentTimestamp(e) {
+        if len(e.Args) > 0 {
+            if value, ok := e.Args[0].(*test_driver.ValueExpr); ok && value.GetInt64() > 0 {
+                return fmt.Sprintf("CURRENT_TIMESTAMP(%d)", value.GetInt64())
+            }
+        }
+
+        return "CURRENT_TIMESTAMP"
+    }
+
+    return restoreExpr(expr)
+}
+
+// restoreExpr 将表达式还原为 SQL。
+func restoreExpr(node ast.Node) string {
+    var sb strings.Builder
+
+    flags := format.RestoreStringSingleQuotes | format.RestoreKeyWordLowercase | format.RestoreNameBackQuotes | format.RestoreStringWithoutCharset
+
+    if err := node.Restore(format.NewRestoreCtx(flags, &sb)); err != nil {
+        return ""
+    }
+
+    return sb.String()
+}
+
+// restoreSchemaExpr 按 information_schema 中 EXPRESSION、GENERATION_EXPRESSION 的写法还原表达式，每个二元运算都加括号。
+func restoreSchemaExpr(expr ast.ExprNode) string {
+    node, _ := expr.Accept(schemaExprVisitor{})
+
+    return restoreExpr(node)
+}
+
+// schemaExprVisitor 去掉原有的括号，为二元运算加括号。
+type schemaExprVisitor struct{}
+
+func (v schemaExprVisitor) Enter(node ast.Node) (ast.Node, bool) {
+    return node, false
+}
+
+func (v schemaExprVisitor) Leave(node ast.Node) (ast.Node, bool) {
+    switch n := node.(type) {
+    case *ast.ParenthesesExpr:
+        return n.Expr, true
+    case *ast.BinaryOperationExpr:
+        return &ast.ParenthesesExpr{Expr: n}, true
+    }
+
+    return node, true
+}
+
+// getPartitionMethod 返回 PARTITION_METHOD 与 PARTITION_EXPRESSION，如 RANGE COLUMNS 与 `a`,`b`。
+func getPartitionMethod(method *ast.PartitionMethod) (string, string) {
+    name := method.Tp.String()
+
+    if method.Linear {
+        name = "LINEAR " + name
+    }
+
+    if method.Expr != nil {
+        return name, restoreExpr(method.Expr)
+    }
+
+    var columnNames []string
+
+    for _, columnName := range method.ColumnNames {
+        columnNames = append(columnNames, fmt.Sprintf("`%s`", columnName.Name.O))
+    }
+
+    if method.Tp == ast.PartitionTypeRange || method.Tp == ast.PartitionTypeList {
+        name += " COLUMNS"
+    }
+
+    return name, strings.Join(columnNames, ",")
+}
+
+// getPartitionDescription 返回 PARTITION_DESCRIPTION，如 10、MAXVALUE、1,2,3。
+func getPartitionDescription(clause ast.PartitionDefinitionClause) string {
+    var values []string
+
+    switch c := clause.(type) {
+    case *ast.PartitionDefinitionClauseLessThan:
+        for _, expr := range c.Exprs {
+            values = append(values, getPartitionValue(expr))
+        }
+    case *ast.PartitionDefinitionClauseIn:
+        for _, exprs := range c.Values {
+            var row []string
+
+            for _, expr := range exprs {
+                row = append(row, getPartitionValue(expr))
+            }
+
+            if len(row) > 1 {
+                values = append(values, "("+strings.Join(row, ",")+")")
+            } else {
+                values = append(values, row...)
+            }
+        }
+    }
+
+    return strings.Join(values, ",")
+}
+
+// getPartitionValue 返回分区值，服务器会计算 TO_DAYS('2024-01-01') 等表达式，这里只计算 TO_DAYS。
+func getPartitionValue(expr ast.ExprNode) string {
+    switch e := expr.(type) {
+    case *ast.MaxValueExpr:
+        return "MAXVALUE"
+    case *ast.FuncCallExpr:
+        if e.FnName.L == "to_days" && len(e.Args) == 1 {
+            if value, ok := e.Args[0].(*test_driver.ValueExpr); ok {
+                for _, layout := range []string{time.DateTime, time.DateOnly} {
+                    if t, err := time.Parse(layout, value.GetString()); err == nil {
+                        // TO_DAYS('1970-01-01') = 719528
+                        return strconv.FormatInt(719528+t.Unix()/86400, 10)
+                    }
+                }
+            }
+        }
+    }
+
+    return restoreExpr(expr)
+}
+
+// ddlTrigger CREATE TRIGGER 语句，Follows、Precedes 用于计算 ACTION_ORDER。
+type ddlTrigger struct {
+    Trigger  Trigger
+    Follows  string
+    Precedes string
+}
+
+// CREATE TRIGGER ...
+func (p *ddlParser) createTrigger(matches []string) {
+    trigger := ddlTrigger{
+        Trigger: Trigger{
+            TriggerCatalog:        "def",
+            TriggerSchema:         p.c.Schema.SchemaName,
+            TriggerName:           getIdentifier(matches[2]),
+            EventManipulation:     strings.ToUpper(matches[4]),
+            EventObjectCatalog:    "def",
+            EventObjectSchema:     p.c.Schema.SchemaName,
+            EventObjectTable:      getIdentifier(matches[5]),
+            ActionStatement:       matches[8],
+            ActionOrientation:     "ROW",
+            ActionTiming:          strings.ToUpper(matches[3]),
+            ActionReferenceOldRow: "OLD",
+            ActionReferenceNewRow: "NEW",
+            DEFINER:               getDdlDefiner(matches[1]),
+        },
+    }
+
+    switch strings.ToUpper(matches[6]) {
+    case "FOLLOWS":
+        trigger.Follows = getIdentifier(matches[7])
+    case "PRECEDES":
+        trigger.Precedes = getIdentifier(matches[7])
+    }
+
+    p.triggers = append(p.triggers, trigger)
+}
+
+// triggerOrders 按出现顺序及 FOLLOWS、PRECEDES 计算 ACTION_ORDER，并按 information_schema 的顺序排列。
+// 引用的触发器可能在之后的文件中，因此先放入引用已确定的触发器，直到没有可放入的为止。
+func (p *ddlParser) triggerOrders() {
+    var (
+        groupNames []string
+        pending    = make(map[string][]ddlTrigger)
+    )
+
+    for _, trigger := range p.triggers {
+        key := getTriggerGroup(trigger.Trigger)
+
+        if _, ok := pending[key]; !ok {
+            groupNames = append(groupNames, key)
+        }
+
+        pending[key] = append(pending[key], trigger)
+    }
+
+    for _, key := range groupNames {
+        var group []Trigger
+
+        for len(pending[key]) > 0 {
+            var rest []ddlTrigger
+
+            for _, trigger := range pending[key] {
+                position, ok := getTriggerPosition(group, trigger)
+
+                if !ok {
+                    rest = append(rest, trigger)
+
+                    continue
+                }
+
+                group = append(group[:position], append([]Trigger{trigger.Trigger}, group[position:]...)...)
+            }
+
+            // 引用不存在或循环引用时按出现顺序放在最后。
+            if len(rest) == len(pending[key]) {
+                for _, trigger := range rest {
+                    group = append(group, trigger.Trigger)
+                }
+
+                rest = nil
+            }
+
+            pending[key] = rest
+        }
+
+        for k, trigger := range group {
+            trigger.ActionOrder = int64(k + 1)
+            p.c.Triggers = append(p.c.Triggers, trigger)
+        }
+    }
+
+    sort.SliceStable(p.c.Triggers, func(i, j int) bool {
+        return getTriggerGroup(p.c.Triggers[i]) < getTriggerGroup(p.c.Triggers[j])
+    })
+}
+
+// getTriggerPosition 返回触发器在 group 中的位置，引用的触发器尚未放入时返回 false。
+func getTriggerPosition(group []Trigger, trigger ddlTrigger) (int, bool) {
+    name := trigger.Follows
+
+    if name == "" {
+        name = trigger.Precedes
+    }
+
+    if name == "" {
+        return len(group), true
+    }
+
+    for k, groupTrigger := range group {
+        if groupTrigger.TriggerName == name {
+            if trigger.Follows != "" {
+                return k + 1, true
+            }
+
+            return k, true
+        }
+    }
+
+    return 0, false
+}
+
+// CREATE PROCEDURE ... 或 CREATE FUNCTION ...
+func (p *ddlParser) createRoutine(text string, matches []int) error {
+    routine := Routine{
+        SpecificName:    getIdentifier(text[matches[6]:matches[7]]),
+        RoutineCatalog:  "def",
+        RoutineSchema:   p.c.Schema.SchemaName,
+        RoutineName:     getIdentifier(text[matches[6]:matches[7]]),
+        RoutineType:     strings.ToUpper(text[matches[4]:matches[5]]),
+        RoutineBody:     "SQL",
+        ParameterStyle:  "SQL",
+        IsDeterministic: "NO",
+        SqlDataAccess:   "CONTAINS SQL",
+        SecurityType:    "DEFINER",
+    }
+
+    if matches[2] >= 0 {
+        routine.DEFINER = getDdlDefiner(text[matches[2]:matches[3]])
+    }
+
+    // 参数列表中可能有 decimal(10,2) 等括号。
+    end := getClosingParen(text, matches[1])
+
+    if end < 0 {
+        return fmt.Errorf("%s `%s` 的参数列表缺少右括号", routine.RoutineType, routine.RoutineName)
+    }
+
+    for k, definition := range splitTopLevel(text[matches[1]:end], ',') {
+        parameter, err := getRoutineParameter(routine, k+1, definition)
+
+        if err != nil {
+            return err
+        }
+
+        p.c.Parameters = append(p.c.Parameters, parameter)
+    }
+
+    rest := strings.TrimSpace(text[end+1:])
+
+    if routine.RoutineType == "FUNCTION" {
+        if !returnsPattern.MatchString(rest) {
+            return fmt.Errorf("FUNCTION `%s` 缺少 RETURNS", routine.RoutineName)
+        }
+
+        returns := getRoutineType(rest[len("RETURNS"):])
+        rest = strings.TrimSpace(strings.TrimSpace(rest[len("RETURNS"):])[len(returns):])
+
+        routine.DtdIdentifier = sql.NullString{String: getDtdIdentifier(returns), Valid: true}
+        routine.DataType = getDataType(routine.DtdIdentifier.String)
+
+        p.c.Parameters = append(p.c.Parameters, Parameter{
+            SpecificCatalog: "def",
+            SpecificSchema:  routine.RoutineSchema,
+            SpecificName:    routine.SpecificName,
+            DataType:        routine.DataType,
+            DtdIdentifier:   routine.DtdIdentifier.String,
+            RoutineType:     routine.RoutineType,
+        })
+    }
+
+    for matched := true; matched; {
+        matched = false
+
+        for k, pattern := range routineCharacteristicPatterns {
+            characteristic := pattern.FindStringSubmatch(rest)
+
+            if characteristic == nil {
+                continue
+            }
+
+            switch k {
+            case 0:
+                routine.RoutineComment = getCommentValue(characteristic[1])
+            case 2:
+                routine.IsDeterministic = lo.Ternary(characteristic[1] == "", "YES", "NO")
+            case 3:
+                routine.SqlDataAccess = strings.ToUpper(strings.Join(strings.Fields(characteristic[1]), " "))
+            case 4:
+                routine.SecurityType = strings.ToUpper(characteristic[1])
+            }
+
+            rest = strings.TrimSpace(rest[len(characteristic[0]):])
+            matched = true
+        }
+    }
+
+    routine.RoutineDefinition = sql.NullString{String: rest, Valid: true}
+
+    p.c.Routines = append(p.c.Routines, routine)
+
+    return nil
+}
+
+// getRoutineParameter 解析 [IN | OUT | INOUT] name type。
+func getRoutineParameter(routine Routine, position int, definition string) (Parameter, error) {
+    fields := strings.Fields(definition)
+
+    parameter := Parameter{
+        SpecificCatalog: "def",
+        SpecificSchema:  routine.RoutineSchema,
+        SpecificName:    routine.SpecificName,
+        OrdinalPosition: position,
+        RoutineType:     routine.RoutineType,
+    }
+
+    if routine.RoutineType == "PROCEDURE" {
+        mode := "IN"
+
+        if len(fields) > 0 && lo.Contains([]string{"IN", "OUT", "INOUT"}, strings.ToUpper(fields[0])) {
+            mode = strings.ToUpper(fields[0])
+            fields = fields[1:]
+        }
+
+        parameter.ParameterMode = sql.NullString{String: mode, Valid: true}
+    }
+
+    if len(fields) < 2 {
+        return parameter, fmt.Errorf("%s `%s` 的参数 `%s` 格式错误", routine.RoutineType, routine.RoutineName, strings.TrimSpace(definition))
+    }
+
+    parameter.ParameterName = sql.NullString{String: strings.Trim(fields[0], "`"), Valid: true}
+    parameter.DtdIdentifier = getDtdIdentifier(strings.Join(fields[1:], " "))
+    parameter.DataType = getDataType(parameter.DtdIdentifier)
+
+    return parameter, nil
+}
+
+// getRoutineType 返回 RETURNS 之后的类型，如 decimal(10,2) unsigned、varchar(64) CHARSET utf8mb4。
+func getRoutineType(text string) string {
+    text = strings.TrimSpace(text)
+    end := strings.IndexFunc(text, func(r rune) bool {
+        return !(r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+    })
+
+    if end < 0 {
+        return text
+    }
+
+    if strings.HasPrefix(text[end:], "(") {
+        if closing := getClosingParen(text, end+1); closing >= 0 {
+            end = closing + 1
+        }
+    }
+
+    for {
+        modifier := returnsModifierPattern.FindString(text[end:])
+
+        if modifier == "" {
+            return text[:end]
+        }
+
+        end += len(modifier)
+    }
+}
+
+// getDtdIdentifier 按 information_schema 的格式返回类型：小写，整数类型去掉显示宽度。
+func getDtdIdentifier(dataType string) string {
+    dataType = strings.Join(strings.Fields(dataType), " ")
+
+    // enum、set 的值保持原样。
+    if strings.ContainsAny(dataType, "'\"") {
+        return dataType
+    }
+
+    dataType = strings.ToLower(strings.NewReplacer(" (", "(", "( ", "(", " )", ")", ", ", ",", " ,", ",").Replace(dataType))
+    dataType = strings.Replace(dataType, "integer", "int", 1)
+    dataType = strings.Replace(dataType, "character set", "charset", 1)
+    dataType = charsetKeywordPattern.ReplaceAllStringFunc(dataType, strings.ToUpper)
+
+    return integerDisplayWidthPattern.ReplaceAllString(dataType, "$1")
+}
+
+// getDataType 返回 DATA_TYPE，即不含长度与修饰的类型名。
+func getDataType(dtdIdentifier string) string {
+    return strings.ToLower(strings.FieldsFunc(dtdIdentifier, func(r rune) bool {
+        return r == '(' || r == ' '
+    })[0])
+}
+
+// rewriteSpatial 将 CREATE TABLE 中的空间列改为 BLOB、SPATIAL KEY 改为 USING RTREE 的普通索引，以便解析器解析，返回空间列。
+func rewriteSpatial(text string) (string, map[string]spatialColumn) {
+    loc := createTablePattern.FindStringIndex(text)
+
+    if loc == nil {
+        return text, nil
+    }
+
+    end := getClosingParen(text, loc[1])
+
+    if end < 0 {
+        return text, nil
+    }
+
+    var (
+        columns     = make(map[string]spatialColumn)
+        changed     bool
+        definitions = splitTopLevel(text[loc[1]:end], ',')
+    )
+
+    for i, definition := range definitions {
+        if matches := spatialIndexPattern.FindStringSubmatch(definition); matches != nil {
+            definitions[i] = matches[1] + " USING RTREE"
+            changed = true
+
+            continue
+        }
+
+        matches := spatialColumnPattern.FindStringSubmatch(definition)
+
+        if matches == nil {
+            continue
+        }
+
+        column := spatialColumn{DataType: strings.ToLower(matches[2])}
+        options := matches[3]
+
+        if srid := sridPattern.FindStringSubmatch(options); srid != nil {
+            srsId, _ := strconv.ParseInt(srid[1], 10, 64)
+            column.SrsId = sql.NullInt64{Int64: srsId, Valid: true}
+            options = strings.Replace(options, srid[0], "", 1)
+        }
+
+        columns[strings.ToLower(getIdentifier(matches[1]))] = column
+        definitions[i] = matches[1] + " BLOB" + options
+        changed = true
+    }
+
+    if !changed {
+        return text, nil
+    }
+
+    return text[:loc[1]] + strings.Join(definitions, ",\n") + text[end:], columns
+}
+
+// rewriteInvisible 去掉列定义中的 [IN]VISIBLE（包括版本注释 /*!80023 INVISIBLE */），返回不可见的列名（小写）。
+func rewriteInvisible(text string) (string, map[string]bool) {
+    loc := createTablePattern.FindStringIndex(text)
+
+    if loc == nil {
+        return text, nil
+    }
+
+    end := getClosingParen(text, loc[1])
+
+    if end < 0 {
+        return text, nil
+    }
+
+    var (
+        columns     = make(map[string]bool)
+        changed     bool
+        definitions = splitTopLevel(text[loc[1]:end], ',')
+    )
+
+    for i, definition := range definitions {
+        matches := columnNamePattern.FindStringSubmatch(definition)
+
+        if matches == nil || indexDefinitionPattern.MatchString(definition) {
+            continue
+        }
+
+        // 列名可能是 visible，只替换列名之后的部分。
+        definitions[i] = matches[0] + visibilityPattern.ReplaceAllStringFunc(definition[len(matches[0]):], func(match string) string {
+            // 字符串与标识符原样保留。
+            if strings.HasPrefix(match, "'") || strings.HasPrefix(match, "`") {
+                return match
+            }
+
+            if strings.Contains(strings.ToUpper(match), "INVISIBLE") {
+                columns[strings.ToLower(getIdentifier(matches[1]))] = true
+            }
+
+            changed = true
+
+            return ""
+        })
+    }
+
+    if !changed {
+        return text, nil
+    }
+
+    return text[:loc[1]] + strings.Join(definitions, ",\n") + text[end:], columns
+}
+
+// getStatsPersistentOptions 返回 CREATE TABLE 中的 STATS_PERSISTENT 与 PACK_KEYS，格式与 CREATE_OPTIONS 一致。
+func getStatsPersistentOptions(text string) []string {
+    loc := createTablePattern.FindStringIndex(text)
+
+    if loc == nil {
+        return nil
+    }
+
+    end := getClosingParen(text, loc[1])
+
+    if end < 0 {
+        return nil
+    }
+
+    var options []string
+
+    for _, matches := range statsPersistentPattern.FindAllStringSubmatch(text[end:], -1) {
+        if matches[1] == "" {
+            continue
+        }
+
+        options = append(options, strings.ToLower(matches[1])+"="+strings.ToUpper(matches[2]))
+    }
+
+    return options
+}
+
+// getClosingParen 返回与 start 之前的左括号匹配的右括号位置，忽略引号中的括号，没有时返回 -1。
+func getClosingParen(text string, start int) int {
+    var (
+        depth = 1
+        quote byte
+    )
+
+    for i := start; i < len(text); i++ {
+        c := text[i]
+
+        switch {
+        case quote != 0:
+            if c == '\\' && quote != '`' {
+                i++
+            } else if c == quote {
+                quote = 0
+            }
+        case c == '\'' || c == '"' || c == '`':
+            quote = c
+        case c == '(':
+            depth++
+        case c == ')':
+            depth--
+
+            if depth == 0 {
+                return i
+            }
+        }
+    }
+
+    return -1
+}
+
+// splitTopLevel 按 sep 拆分，忽略括号与引号中的 sep，去掉空白项。
+func splitTopLevel(text string, sep byte) []string {
+    var (
+        parts []string
+        depth int
+        quote byte
+        start int
+    )
+
+    for i := 0; i <= len(text); i++ {
+        if i == len(text) {
+            if part := strings.TrimSpace(text[start:]); part != "" {
+                parts = append(parts, part)
+            }
+
+            break
+        }
+
+        c := text[i]
+
+        switch {
+        case quote != 0:
+            if c == '\\' && quote != '`' {
+                i++
+            } else if c == quote {
+                quote = 0
+            }
+        case c == '\'' || c == '"' || c == '`':
+            quote = c
+        case c == '(':
+            depth++
+        case c == ')':
+            depth--
+        case c == sep && depth == 0:
+            if part := strings.TrimSpace(text[start:i]); part != "" {
+                parts = append(parts, part)
+            }
+
+            start = i + 1
+        }
+    }
+
+    return parts
+}
+
+// getCommentValue 还原单引号字符串中的转义。
+func getCommentValue(comment string) string {
+    return strings.NewReplacer("''", "'", "\\'", "'", "\\\\", "\\").Replace(comment)
+}
+
+// CREATE EVENT ...
+//
+// 只支持字符串形式的时间，如 STARTS '2024-01-01 03:00:00'；CURRENT_TIMESTAMP 等表达式视为未指定，不参与比对。
+func (p *ddlParser) createEvent(matches []string) error {
+    event := Event{
+        EventCatalog: "def",
+        EventSchema:  p.c.Schema.SchemaName,
+        EventName:    getIdentifier(matches[2]),
+        DEFINER:      getDdlDefiner(matches[1]),
+        TimeZone:     "SYSTEM",
+        EventBody:    "SQL",
+        STATUS:       "ENABLED",
+        OnCompletion: "NOT PRESERVE",
+    }
+
+    if at := eventAtPattern.FindStringSubmatch(matches[3]); at != nil {
+        event.EventType = "ONE TIME"
+        event.ExecuteAt = getEventTime(at[1])
+    } else if every := eventEveryPattern.FindStringSubmatch(matches[3]); every != nil {
+        event.EventType = "RECURRING"
+        event.IntervalValue = sql.NullString{String: strings.Trim(every[1], "'"), Valid: true}
+        event.IntervalField = sql.NullString{String: strings.ToUpper(every[2]), Valid: true}
+        event.STARTS = getEventTime(every[3])
+        event.ENDS = getEventTime(every[4])
+    } else {
+        return fmt.Errorf("EVENT `%s` 的 ON SCHEDULE 格式错误", event.EventName)
+    }
+
+    rest := matches[4]
+
+    for matched := true; matched && rest != ""; {
+        matched = false
+
+        for k, pattern := range eventOptionPatterns {
+            option := pattern.FindStringSubmatch(rest)
+
+            if option == nil {
+                continue
+            }
+
+            switch k {
+            case 0:
+                event.OnCompletion = lo.Ternary(option[1] == "", "PRESERVE", "NOT PRESERVE")
+            case 1:
+                event.STATUS = "ENABLED"
+
+                if strings.EqualFold(option[1], "DISABLE") {
+                    event.STATUS = "DISABLED"
+                } else if !strings.EqualFold(option[1], "ENABLE") {
+                    event.STATUS = "SLAVESIDE_DISABLED"
+                }
+            case 2:
+                event.EventComment = getCommentValue(option[1])
+            case 3:
+                event.EventDefinition = option[1]
+            }
+
+            rest = strings.TrimSpace(rest[len(option[0]):])
+            matched = true
+        }
+    }
+
+    if event.EventDefinition == "" {
+        return fmt.Errorf("EVENT `%s` 缺少 DO", event.EventName)
+    }
+
+    p.c.Events = append(p.c.Events, event)
+
+    return nil
+}
+
+// getEventTime 解析字符串形式的时间，表达式返回无效值。
+func getEventTime(value string) sql.NullTime {
+    value = strings.TrimSpace(value)
+
+    if len(value) < 2 || value[0] != '\'' || value[len(value)-1] != '\'' {
+        return sql.NullTime{}
+    }
+
+    for _, layout := range []string{time.DateTime, time.DateOnly} {
+        if t, err := time.Parse(layout, value[1:len(value)-1]); err == nil {
+            return sql.NullTime{Time: t, Valid: true}
+        }
+    }
+
+    return sql.NullTime{}
+}
+
+// ddlStatement 一条去掉开头注释的语句，Line 为起始行号。
+type ddlStatement struct {
+    Line int
+    Text string
+}
+
+// splitStatements 按分隔符拆分语句，支持 DELIMITER，忽略引号与注释中的分隔符。
+func splitStatements(ddl string) []ddlStatement {
+    var (
+        statements []ddlStatement
+        sb         strings.Builder
+        delimiter  = ";"
+        line       = 1
+        startLine  = 1
+        quote      byte
+    )
+
+    emit := func() {
+        if text := trimLeadingComments(sb.String()); text != "" {
+            statements = append(statements, ddlStatement{Line: startLine, Text: text})
+        }
+
+        sb.Reset()
+    }
+
+    for i := 0; i < len(ddl); i++ {
+        c := ddl[i]
+
+        if quote == 0 && trimLeadingComments(sb.String()) == "" {
+            startLine = line
+
+            // DELIMITER ...
+            if (i == 0 || ddl[i-1] == '\n') && len(ddl)-i > 10 && strings.EqualFold(ddl[i:i+10], "DELIMITER ") {
+                end := strings.IndexByte(ddl[i:], '\n')
+
+                if end < 0 {
+                    end = len(ddl) - i
+                }
+
+                if fields := strings.Fields(ddl[i+10 : i+end]); len(fields) > 0 {
+                    delimiter = fields[0]
+                }
+
+                sb.Reset()
+                i += end - 1
+
+                continue
+            }
+        }
+
+        switch {
+        case quote != 0:
+            sb.WriteByte(c)
+
+            if c == '\\' && quote != '`' && i+1 < len(ddl) {
+                i++
+                sb.WriteByte(ddl[i])
+            } else if c == quote {
+                quote = 0
+            }
+        case c == '\'' || c == '"' || c == '`':
+            quote = c
+            sb.WriteByte(c)
+        case c == '#' || (c == '-' && strings.HasPrefix(ddl[i:], "-- ")):
+            end := strings.IndexByte(ddl[i:], '\n')
+
+            if end < 0 {
+                end = len(ddl) - i
+            }
+
+            sb.WriteString(ddl[i : i+end])
+            i += end - 1
+        case c == '/' && strings.HasPrefix(ddl[i:], "/*"):
+            end := strings.Index(ddl[i+2:], "*/")
+
+            if end < 0 {
+                end = len(ddl) - i - 4
+            }
+
+            sb.WriteString(ddl[i : i+end+4])
+            line += strings.Count(ddl[i:i+end+4], "\n")
+            i += end + 3
+        case strings.HasPrefix(ddl[i:], delimiter):
+            emit()
+            i += len(delimiter) - 1
+        default:
+            sb.WriteByte(c)
+        }
+
+        if c == '\n' {
+            line++
+        }
+    }
+
+    emit()
+
+    return statements
+}
+
+// trimLeadingComments 去掉语句开头的注释。
+func trimLeadingComments(text string) string {
+    for {
+        text = strings.TrimSpace(text)
+
+        switch {
+        case strings.HasPrefix(text, "#"), strings.HasPrefix(text, "-- "), text == "--":
+            end := strings.IndexByte(text, '\n')
+
+            if end < 0 {
+                return ""
+            }
+
+            text = text[end+1:]
+        case strings.HasPrefix(text, "/*") && !strings.HasPrefix(text, "/*!"):
+            end := strings.Index(text, "*/")
+
+            if end < 0 {
+                return ""
+            }
+
+            text = text[end+2:]
+        default:
+            return text
+        }
+    }
+}
+
+// getIdentifier 返回去掉库名与反引号的对象名。
+func getIdentifier(identifier string) string {
+    if strings.HasSuffix(identifier, "`") {
+        identifier = identifier[strings.LastIndex(identifier[:len(identifier)-1], "`")+1 : len(identifier)-1]
+    } else if i := strings.LastIndex(identifier, "."); i >= 0 {
+        identifier = identifier[i+1:]
+    }
+
+    return identifier
+}
+
+// getDdlDefiner 将 `user`@`host` 转换为 information_schema 中的 user@host，CURRENT_USER 返回空。
+func getDdlDefiner(definer string) string {
+    if definer == "" || strings.HasPrefix(strings.ToUpper(definer), "CURRENT_USER") {
+        return ""
+    }
+
+    return strings.NewReplacer("`", "", "'", "", "\"", "").Replace(definer)
+}
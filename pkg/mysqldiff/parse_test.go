@@ -0,0 +1,461 @@
+package mysqldiff
+
+import (
+    "fmt"
+    "os"
+    "path/filepath"
+    "slices"
+    "strings"
+    "testing"
+
+    "github.com/samber/lo"
+)
+
+// getColumn 返回表 t 的列，不存在时终止测试。
+func getColumn(t *testing.T, catalog *Catalog, name string) Column {
+    t.Helper()
+
+    for _, column := range catalog.Columns {
+        if column.TableName == "t" && column.ColumnName == name {
+            return column
+        }
+    }
+
+    t.Fatalf("column %q not found", name)
+
+    return Column{}
+}
+
+func TestParseDDLColumnDefault(t *testing.T) {
+    tests := []struct {
+        name     string
+        column   string
+        nullable string
+        value    string
+        valid    bool
+        extra    string
+    }{
+        {"无默认值", "a int", "YES", "", false, ""},
+        {"NULL", "a varchar(10) DEFAULT NULL", "YES", "", false, ""},
+        {"字符串", "a varchar(10) NOT NULL DEFAULT 'x y'", "NO", "x y", true, ""},
+        {"字符串 now", "a varchar(10) DEFAULT 'now'", "YES", "now", true, ""},
+        {"空字符串", "a varchar(10) DEFAULT ''", "YES", "", true, ""},
+        {"整数", "a int DEFAULT '1'", "YES", "1", true, ""},
+        {"小数补足精度", "a decimal(10,2) DEFAULT 1.5", "YES", "1.50", true, ""},
+        {"位", "a bit(3) DEFAULT b'101'", "YES", "b'101'", true, ""},
+        {"枚举", "a enum('x','y') NOT NULL DEFAULT 'x'", "NO", "x", true, ""},
+        {"当前时间", "a timestamp NULL DEFAULT CURRENT_TIMESTAMP", "YES", "CURRENT_TIMESTAMP", true, "DEFAULT_GENERATED"},
+        {"当前时间同义词", "a datetime DEFAULT now()", "YES", "CURRENT_TIMESTAMP", true, "DEFAULT_GENERATED"},
+        {"当前时间与更新", "a timestamp(3) NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3)", "YES", "CURRENT_TIMESTAMP(3)", true, "DEFAULT_GENERATED on update CURRENT_TIMESTAMP(3)"},
+        {"表达式", "a varchar(36) DEFAULT (uuid())", "YES", "uuid()", true, "DEFAULT_GENERATED"},
+        {"自增", "a int NOT NULL AUTO_INCREMENT PRIMARY KEY", "NO", "", false, "auto_increment"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            column := getColumn(t, mustParseDDL(t, "CREATE TABLE t ("+tt.column+");"), "a")
+
+            if column.IsNullable != tt.nullable {
+                t.Errorf("IsNullable = %q, want %q", column.IsNullable, tt.nullable)
+            }
+
+            if column.ColumnDefault.String != tt.value || column.ColumnDefault.Valid != tt.valid {
+                t.Errorf("ColumnDefault = %+v, want %q (valid %v)", column.ColumnDefault, tt.value, tt.valid)
+            }
+
+            if column.EXTRA != tt.extra {
+                t.Errorf("EXTRA = %q, want %q", column.EXTRA, tt.extra)
+            }
+        })
+    }
+}
+
+func TestParseDDLColumnType(t *testing.T) {
+    tests := []struct {
+        name       string
+        column     string
+        options    string
+        dataType   string
+        columnType string
+        charset    string
+        collation  string
+    }{
+        {"整数", "a int unsigned", "", "int", "int unsigned", "", ""},
+        {"字符串默认字符集", "a varchar(10)", "", "varchar", "varchar(10)", "utf8mb4", "utf8mb4_0900_ai_ci"},
+        {"表字符集", "a char(2)", "DEFAULT CHARSET=latin1", "char", "char(2)", "latin1", "latin1_swedish_ci"},
+        {"列排序规则", "a text COLLATE utf8mb4_bin", "", "text", "text", "utf8mb4", "utf8mb4_bin"},
+        {"枚举", "a enum('x','y')", "", "enum", "enum('x','y')", "utf8mb4", "utf8mb4_0900_ai_ci"},
+        {"时间精度", "a datetime(6)", "", "datetime", "datetime(6)", "", ""},
+        {"空间", "a point NOT NULL SRID 4326", "", "point", "point", "", ""},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            column := getColumn(t, mustParseDDL(t, "CREATE TABLE t ("+tt.column+") "+tt.options+";"), "a")
+
+            if column.DataType != tt.dataType || column.ColumnType != tt.columnType {
+                t.Errorf("type = %q %q, want %q %q", column.DataType, column.ColumnType, tt.dataType, tt.columnType)
+            }
+
+            if column.CharacterSetName.String != tt.charset || column.CollationName.String != tt.collation {
+                t.Errorf("charset = %q %q, want %q %q", column.CharacterSetName.String, column.CollationName.String, tt.charset, tt.collation)
+            }
+        })
+    }
+}
+
+func TestParseDDLGeneratedColumn(t *testing.T) {
+    tests := []struct {
+        name       string
+        column     string
+        expression string
+        extra      string
+    }{
+        {"虚拟列", "b int AS (a + 1)", "(`a`+1)", "VIRTUAL GENERATED"},
+        {"存储列", "b varchar(20) GENERATED ALWAYS AS (concat(a, 'x')) STORED", "concat(`a`, 'x')", "STORED GENERATED"},
+        {"保留字符串", "b varchar(20) AS (concat(a, ' A B')) VIRTUAL", "concat(`a`, ' A B')", "VIRTUAL GENERATED"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            column := getColumn(t, mustParseDDL(t, "CREATE TABLE t (a varchar(10), "+tt.column+");"), "b")
+
+            if column.GenerationExpression != tt.expression {
+                t.Errorf("GenerationExpression = %q, want %q", column.GenerationExpression, tt.expression)
+            }
+
+            if column.EXTRA != tt.extra {
+                t.Errorf("EXTRA = %q, want %q", column.EXTRA, tt.extra)
+            }
+
+            if column.ColumnDefault.Valid {
+                t.Errorf("ColumnDefault = %+v, want NULL", column.ColumnDefault)
+            }
+        })
+    }
+}
+
+func TestParseDDLIndex(t *testing.T) {
+    tests := []struct {
+        name  string
+        index string
+        want  []string
+    }{
+        {"主键", "PRIMARY KEY (a, b)", []string{"PRIMARY a 0 A BTREE YES", "PRIMARY b 0 A BTREE YES"}},
+        {"唯一索引", "UNIQUE KEY uk (a)", []string{"uk a 0 A BTREE YES"}},
+        {"自动命名", "KEY (a), KEY (a, b)", []string{"a a 1 A BTREE YES", "a_2 a 1 A BTREE YES", "a_2 b 1 A BTREE YES"}},
+        {"降序", "KEY idx (a, b DESC)", []string{"idx a 1 A BTREE YES", "idx b 1 D BTREE YES"}},
+        {"前缀", "KEY idx (a(5))", []string{"idx a 1 A BTREE YES sub_part=5"}},
+        {"不可见", "KEY idx (a) INVISIBLE", []string{"idx a 1 A BTREE NO"}},
+        {"函数索引", "KEY idx ((lower(a)))", []string{"idx  1 A BTREE YES expression=lower(`a`)"}},
+        {"全文索引", "FULLTEXT KEY ft (c) WITH PARSER ngram", []string{"ft c 1  FULLTEXT YES parser=ngram"}},
+        {"空间索引", "SPATIAL KEY sp (d)", []string{"sp d 1 A SPATIAL YES"}},
+        {"InnoDB 以 BTREE 代替 HASH", "KEY idx (a) USING HASH", []string{"idx a 1 A BTREE YES"}},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            catalog := mustParseDDL(t, "CREATE TABLE t (a varchar(10) NOT NULL, b int NOT NULL, c text, d point NOT NULL SRID 0, "+tt.index+");")
+
+            var got []string
+
+            for _, s := range catalog.Statistics {
+                row := fmt.Sprintf("%s %s %d %s %s %s", s.IndexName, s.ColumnName, s.NonUnique, s.COLLATION.String, s.IndexType, s.IsVisible.String)
+
+                if s.SubPart.Valid {
+                    row += fmt.Sprintf(" sub_part=%d", s.SubPart.Int32)
+                }
+
+                if s.EXPRESSION.Valid {
+                    row += " expression=" + s.EXPRESSION.String
+                }
+
+                if s.Parser != "" {
+                    row += " parser=" + s.Parser
+                }
+
+                got = append(got, row)
+            }
+
+            if !slices.Equal(got, tt.want) {
+                t.Fatalf("statistics = %q, want %q", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestParseDDLPartition(t *testing.T) {
+    tests := []struct {
+        name      string
+        partition string
+        want      []string
+    }{
+        {
+            name:      "RANGE",
+            partition: "PARTITION BY RANGE (id) (PARTITION p0 VALUES LESS THAN (10), PARTITION p1 VALUES LESS THAN MAXVALUE)",
+            want:      []string{"p0 1 RANGE `id` 10", "p1 2 RANGE `id` MAXVALUE"},
+        },
+        {
+            name:      "RANGE COLUMNS",
+            partition: "PARTITION BY RANGE COLUMNS (id, d) (PARTITION p0 VALUES LESS THAN (10, '2024-01-01'))",
+            want:      []string{"p0 1 RANGE COLUMNS `id`,`d` 10,'2024-01-01'"},
+        },
+        {
+            name:      "LIST",
+            partition: "PARTITION BY LIST (id) (PARTITION p0 VALUES IN (1, 2), PARTITION p1 VALUES IN (3))",
+            want:      []string{"p0 1 LIST `id` 1,2", "p1 2 LIST `id` 3"},
+        },
+        {
+            name:      "HASH 表达式",
+            partition: "PARTITION BY HASH (year(d)) PARTITIONS 2",
+            want:      []string{"p0 1 HASH year(`d`) ", "p1 2 HASH year(`d`) "},
+        },
+        {
+            name:      "KEY",
+            partition: "PARTITION BY KEY (id) PARTITIONS 3",
+            want:      []string{"p0 1 KEY `id` ", "p1 2 KEY `id` ", "p2 3 KEY `id` "},
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            catalog := mustParseDDL(t, "CREATE TABLE t (id int NOT NULL, d date NOT NULL) "+tt.partition+";")
+
+            var got []string
+
+            for _, p := range catalog.Partitions {
+                got = append(got, fmt.Sprintf("%s %d %s %s %s", p.PartitionName.String, p.PartitionOrdinalPosition.Int64, p.PartitionMethod.String, p.PartitionExpression.String, p.PartitionDescription.String))
+            }
+
+            if !slices.Equal(got, tt.want) {
+                t.Fatalf("partitions = %q, want %q", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestParseDDLCheck(t *testing.T) {
+    tests := []struct {
+        name  string
+        check string
+        want  []string
+    }{
+        {"命名约束", "CONSTRAINT chk CHECK (a > 0)", []string{"chk (`a`>0) YES"}},
+        {"不强制检查", "CONSTRAINT chk CHECK (a > 0) NOT ENFORCED", []string{"chk (`a`>0) NO"}},
+        {"自动命名", "CHECK (a > 0), CHECK (b <> 'x')", []string{"t_chk_1 (`a`>0) YES", "t_chk_2 (`b`!='x') YES"}},
+        {"列约束", "c int CHECK (c < 10)", []string{"t_chk_1 (`c`<10) YES"}},
+        {"保留字符串", "CONSTRAINT chk CHECK (b IN ('A B'))", []string{"chk (`b` in ('A B')) YES"}},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            catalog := mustParseDDL(t, "CREATE TABLE t (a int, b varchar(10), "+tt.check+");")
+
+            var got []string
+
+            for _, c := range catalog.CheckConstraints {
+                constraint, _ := lo.Find(catalog.TableConstraints, func(constraint TableConstraints) bool {
+                    return constraint.ConstraintName == c.ConstraintName
+                })
+
+                got = append(got, fmt.Sprintf("%s %s %s", c.ConstraintName, c.CheckClause, constraint.ENFORCED))
+            }
+
+            if !slices.Equal(got, tt.want) {
+                t.Fatalf("checks = %q, want %q", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestParseDDLStatements(t *testing.T) {
+    catalog := mustParseDDL(t, `-- 导出文件
+SET NAMES utf8mb4;
+DROP TABLE IF EXISTS t;
+CREATE TABLE t (a int);
+INSERT INTO t VALUES (1);
+CREATE VIEW v AS SELECT a FROM t;`)
+
+    if len(catalog.Tables) != 2 || catalog.Tables[0].TableName != "t" || catalog.Views[0].TableName != "v" {
+        t.Fatalf("tables = %+v, want t and v", catalog.Tables)
+    }
+}
+
+func TestParseDDLError(t *testing.T) {
+    _, err := ParseDDL("db", "CREATE TABLE a (id int);\n\nCREATE TABLE t (a int,);")
+
+    if err == nil || !strings.HasPrefix(err.Error(), "第 3 行：") {
+        t.Fatalf("err = %v, want error at line 3", err)
+    }
+}
+
+func TestReadDDL(t *testing.T) {
+    dir := t.TempDir()
+
+    files := map[string]string{
+        "a.sql":          "CREATE TABLE a (id int);",
+        "sub/b.SQL":      "CREATE TABLE b (id int);",
+        "sub/deep/v.sql": "CREATE VIEW v AS SELECT id FROM a;",
+        "README.md":      "CREATE TABLE ignored (id int);",
+        "bad.txt":        "not sql",
+    }
+
+    for name, content := range files {
+        path := filepath.Join(dir, name)
+
+        if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+            t.Fatal(err)
+        }
+
+        if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    tests := []struct {
+        name   string
+        path   string
+        tables []string
+    }{
+        {"目录", dir, []string{"a", "b", "v"}},
+        {"子目录", filepath.Join(dir, "sub"), []string{"b", "v"}},
+        {"单个文件不限扩展名", filepath.Join(dir, "README.md"), []string{"ignored"}},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            catalog, err := ReadDDL(tt.path, "db")
+
+            if err != nil {
+                t.Fatal(err)
+            }
+
+            var tables []string
+
+            for _, table := range catalog.Tables {
+                tables = append(tables, table.TableName)
+            }
+
+            slices.Sort(tables)
+
+            if !slices.Equal(tables, tt.tables) || catalog.Schema.SchemaName != "db" {
+                t.Fatalf("tables = %v in %q, want %v", tables, catalog.Schema.SchemaName, tt.tables)
+            }
+        })
+    }
+
+    if _, err := ReadDDL(filepath.Join(dir, "bad.txt"), "db"); err == nil || !strings.Contains(err.Error(), "bad.txt") {
+        t.Errorf("err = %v, want error naming the file", err)
+    }
+
+    if _, err := ReadDDL(filepath.Join(dir, "missing"), "db"); err == nil {
+        t.Error("ReadDDL of a missing path succeeded")
+    }
+}
+
+func TestParseDDLRoundTrip(t *testing.T) {
+    tests := []struct {
+        name string
+        ddl  string
+    }{
+        {
+            name: "列与默认值",
+            ddl: `CREATE DATABASE db DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_bin;
+CREATE TABLE t (
+    id bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+    a varchar(10) NOT NULL DEFAULT 'now',
+    b timestamp(3) NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    c decimal(10,2) DEFAULT 1.5,
+    d varchar(36) DEFAULT (uuid()),
+    e bit(3) DEFAULT b'101',
+    f enum('x','y') NOT NULL DEFAULT 'x',
+    g json,
+    h text CHARACTER SET latin1,
+    i int INVISIBLE,
+    PRIMARY KEY (id)
+) ENGINE=InnoDB AUTO_INCREMENT=100 DEFAULT CHARSET=utf8mb4 ROW_FORMAT=DYNAMIC COMMENT='表';`,
+        },
+        {
+            name: "生成列与约束",
+            ddl: `CREATE TABLE t (
+    a varchar(10),
+    b varchar(20) AS (concat(a, ' A B')) VIRTUAL,
+    c int GENERATED ALWAYS AS (length(a)) STORED,
+    CONSTRAINT chk CHECK (a IN ('x', 'Y Z')) NOT ENFORCED,
+    CHECK (c > 0)
+);`,
+        },
+        {
+            name: "索引",
+            ddl: `CREATE TABLE t (
+    a varchar(10) NOT NULL,
+    b int NOT NULL,
+    c text,
+    d point NOT NULL SRID 4326,
+    UNIQUE KEY uk (a, b DESC),
+    KEY pre (a(5)) COMMENT '前缀',
+    KEY inv (b) INVISIBLE,
+    KEY fn ((lower(a))),
+    FULLTEXT KEY ft (c) WITH PARSER ngram,
+    SPATIAL KEY sp (d)
+);`,
+        },
+        {
+            name: "外键",
+            ddl: `CREATE TABLE p (id int NOT NULL, PRIMARY KEY (id));
+CREATE TABLE t (
+    id int NOT NULL,
+    p_id int,
+    PRIMARY KEY (id),
+    KEY p_id (p_id),
+    CONSTRAINT fk FOREIGN KEY (p_id) REFERENCES p (id) ON DELETE CASCADE
+);`,
+        },
+        {
+            name: "分区",
+            ddl: `CREATE TABLE r (id int NOT NULL) PARTITION BY RANGE (id) (PARTITION p0 VALUES LESS THAN (10), PARTITION p1 VALUES LESS THAN MAXVALUE);
+CREATE TABLE l (id int NOT NULL, d date NOT NULL) PARTITION BY LIST COLUMNS (id) (PARTITION p0 VALUES IN (1, 2), PARTITION p1 VALUES IN (3));
+CREATE TABLE h (id int NOT NULL, d date NOT NULL) PARTITION BY HASH (year(d)) PARTITIONS 4;`,
+        },
+        {
+            name: "视图、触发器、存储过程与事件",
+            ddl: `CREATE TABLE t (id int NOT NULL, n int);
+CREATE TABLE log (id int);
+CREATE VIEW v AS SELECT id, n FROM t WHERE n > 0;
+CREATE TRIGGER t_ai AFTER INSERT ON t FOR EACH ROW INSERT INTO log VALUES (NEW.id);
+CREATE TRIGGER t_ai2 AFTER INSERT ON t FOR EACH ROW FOLLOWS t_ai INSERT INTO log VALUES (NEW.id + 1);
+DELIMITER ;;
+CREATE PROCEDURE p(IN a int, OUT b varchar(10))
+BEGIN
+    SELECT a INTO b;
+END;;
+CREATE FUNCTION f(a int) RETURNS int DETERMINISTIC RETURN a + 1;;
+DELIMITER ;
+CREATE EVENT e ON SCHEDULE EVERY 1 DAY STARTS '2024-01-01 00:00:00' DO DELETE FROM log;`,
+        },
+    }
+
+    options := Options{Comment: true, Foreign: true, Database: true, AutoIncrement: AutoIncrementCreate, TableOptions: DefaultTableOptions}
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            catalog := mustParseDDL(t, tt.ddl)
+
+            var contents []string
+
+            for _, file := range SchemaFiles(catalog) {
+                contents = append(contents, file.Content)
+            }
+
+            again := mustParseDDL(t, strings.Join(contents, "\n"))
+
+            if result := Compare(catalog, again, options); len(result.Changes) > 0 {
+                t.Fatalf("round trip differs:\n%s\n\nschema files:\n%s", result.Script(), strings.Join(contents, "\n"))
+            }
+
+            if result := Compare(again, catalog, options); len(result.Changes) > 0 {
+                t.Fatalf("round trip differs:\n%s", result.Script())
+            }
+        })
+    }
+}
@@ -0,0 +1,176 @@
+package mysqldiff
+
+import (
+    "fmt"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+)
+
+type FileStatus string
+
+const (
+    FileCreated FileStatus = "CREATED"
+    FileUpdated FileStatus = "UPDATED"
+    FileDeleted FileStatus = "DELETED"
+)
+
+// schemaDirs 按对象类型存放 DDL 文件的子目录。
+var schemaDirs = []string{"tables", "views", "triggers", "procedures", "functions", "events"}
+
+// SchemaFile 一个数据库对象的 DDL 文件，Path 为相对路径。
+type SchemaFile struct {
+    Path    string
+    Content string
+}
+
+// PulledFile 写入目录时发生变化的文件。
+type PulledFile struct {
+    Path   string
+    Status FileStatus
+}
+
+// SchemaFiles 返回数据库结构对应的 DDL 文件，同一结构的输出始终一致，可直接纳入版本管理。
+func SchemaFiles(catalog *Catalog) []SchemaFile {
+    files := []SchemaFile{{
+        Path:    "database.sql",
+        Content: getCreateDatabase(catalog.Schema.SchemaName, catalog.Schema) + "\n",
+    }}
+
+    // 与空库比对，得到全部对象的创建语句。
+    result := Compare(catalog, &Catalog{Schema: catalog.Schema}, Options{Comment: true, Foreign: true, TableOptions: DefaultTableOptions})
+    renderer := result.Renderer()
+
+    for _, change := range result.Changes {
+        dir := getSchemaDir(change)
+
+        if dir == "" {
+            continue
+        }
+
+        files = append(files, SchemaFile{
+            Path:    filepath.Join(dir, getSchemaFileName(change.ObjectName())+".sql"),
+            Content: strings.Join(getScriptStatements(change, renderer.Render(change)), "\n") + "\n",
+        })
+    }
+
+    return files
+}
+
+// WriteSchemaFiles 将 DDL 文件写入 dir，只覆盖内容有变化的文件；prune 时删除已不存在的对象的文件。
+func WriteSchemaFiles(dir string, files []SchemaFile, prune bool) ([]PulledFile, error) {
+    var pulled []PulledFile
+
+    paths := make(map[string]bool)
+
+    for _, file := range files {
+        // 不写入目录以外的文件。
+        if !filepath.IsLocal(file.Path) {
+            return pulled, fmt.Errorf("文件路径 `%s` 不在目录中。", file.Path)
+        }
+
+        path := filepath.Join(dir, file.Path)
+        paths[path] = true
+
+        status := FileUpdated
+        content, err := os.ReadFile(path)
+
+        if os.IsNotExist(err) {
+            status = FileCreated
+        } else if err != nil {
+            return pulled, err
+        } else if normalizeSchemaFile(string(content)) == normalizeSchemaFile(file.Content) {
+            continue
+        }
+
+        if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+            return pulled, err
+        }
+
+        if err := os.WriteFile(path, []byte(file.Content), 0644); err != nil {
+            return pulled, err
+        }
+
+        pulled = append(pulled, PulledFile{Path: path, Status: status})
+    }
+
+    if !prune {
+        return pulled, nil
+    }
+
+    for _, schemaDir := range schemaDirs {
+        entries, err := os.ReadDir(filepath.Join(dir, schemaDir))
+
+        if os.IsNotExist(err) {
+            continue
+        }
+
+        if err != nil {
+            return pulled, err
+        }
+
+        for _, entry := range entries {
+            path := filepath.Join(dir, schemaDir, entry.Name())
+
+            if entry.IsDir() || !strings.EqualFold(filepath.Ext(path), ".sql") || paths[path] {
+                continue
+            }
+
+            if err := os.Remove(path); err != nil {
+                return pulled, err
+            }
+
+            pulled = append(pulled, PulledFile{Path: path, Status: FileDeleted})
+        }
+    }
+
+    sort.SliceStable(pulled, func(i, j int) bool {
+        return pulled[i].Path < pulled[j].Path
+    })
+
+    return pulled, nil
+}
+
+// getSchemaDir 返回对象类型对应的子目录。
+func getSchemaDir(change Change) string {
+    switch c := change.(type) {
+    case *TableChange:
+        return "tables"
+    case *ViewChange:
+        return "views"
+    case *TriggerChange:
+        return "triggers"
+    case *RoutineChange:
+        return strings.ToLower(c.RoutineType) + "s"
+    case *EventChange:
+        return "events"
+    }
+
+    return ""
+}
+
+// getSchemaFileName 返回对象名对应的文件名，路径分隔符、% 等不能用于文件名的字符写作 %XX。
+func getSchemaFileName(name string) string {
+    var b strings.Builder
+
+    for i := 0; i < len(name); i++ {
+        if c := name[i]; c < 0x20 || c == 0x7f || strings.IndexByte("/\\%:*?\"<>|", c) >= 0 {
+            fmt.Fprintf(&b, "%%%02X", c)
+        } else {
+            b.WriteByte(c)
+        }
+    }
+
+    // . 与 .. 不能作为文件名，点号开头的文件会被隐藏。
+    if strings.HasPrefix(b.String(), ".") {
+        return "%2E" + b.String()[1:]
+    }
+
+    return b.String()
+}
+
+// normalizeSchemaFile 忽略换行符与首尾空白的差异。
+func normalizeSchemaFile(content string) string {
+    return strings.TrimSpace(strings.ReplaceAll(content, "\r\n", "\n"))
+}
@@ -0,0 +1,93 @@
+package mysqldiff
+
+import (
+    "os"
+    "path/filepath"
+    "slices"
+    "strings"
+    "testing"
+)
+
+func TestGetSchemaFileName(t *testing.T) {
+    tests := []struct {
+        name string
+        want string
+    }{
+        {"users", "users"},
+        {"order items", "order items"},
+        {"../evil", "%2E.%2Fevil"},
+        {"a/b\\c", "a%2Fb%5Cc"},
+        {"..", "%2E."},
+        {"100%", "100%25"},
+        {"a:b", "a%3Ab"},
+    }
+
+    for _, tt := range tests {
+        if got := getSchemaFileName(tt.name); got != tt.want {
+            t.Errorf("getSchemaFileName(%q) = %q, want %q", tt.name, got, tt.want)
+        }
+    }
+}
+
+func TestSchemaFiles(t *testing.T) {
+    catalog := mustParseDDL(t, "CREATE TABLE `../evil` (id int); CREATE TABLE `a/b` (id int); CREATE TABLE users (id int); CREATE VIEW `..` AS SELECT 1 AS a;")
+
+    var paths []string
+
+    for _, file := range SchemaFiles(catalog) {
+        if !filepath.IsLocal(file.Path) || strings.Count(filepath.ToSlash(file.Path), "/") > 1 {
+            t.Errorf("path %q is outside its directory", file.Path)
+        }
+
+        paths = append(paths, filepath.ToSlash(file.Path))
+    }
+
+    for _, path := range []string{"database.sql", "tables/%2E.%2Fevil.sql", "tables/a%2Fb.sql", "tables/users.sql", "views/%2E..sql"} {
+        if !slices.Contains(paths, path) {
+            t.Errorf("paths %v do not contain %q", paths, path)
+        }
+    }
+}
+
+func TestWriteSchemaFiles(t *testing.T) {
+    root := t.TempDir()
+    dir := filepath.Join(root, "schema")
+
+    catalog := mustParseDDL(t, "CREATE TABLE `../evil` (id int); CREATE TABLE users (id int);")
+
+    pulled, err := WriteSchemaFiles(dir, SchemaFiles(catalog), false)
+
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if len(pulled) != 3 {
+        t.Fatalf("pulled = %v, want 3 files", pulled)
+    }
+
+    // 内容不变时不再写入。
+    if pulled, err = WriteSchemaFiles(dir, SchemaFiles(catalog), true); err != nil || len(pulled) > 0 {
+        t.Fatalf("pulled = %v, err = %v, want no changes", pulled, err)
+    }
+
+    for _, path := range []string{"../evil.sql", "/tmp/evil.sql", "tables/../../evil.sql"} {
+        if _, err := WriteSchemaFiles(dir, []SchemaFile{{Path: path, Content: "x"}}, false); err == nil {
+            t.Errorf("WriteSchemaFiles(%q) succeeded", path)
+        }
+    }
+
+    if _, err := os.Stat(filepath.Join(root, "evil.sql")); !os.IsNotExist(err) {
+        t.Errorf("file written outside the directory: %v", err)
+    }
+
+    // 读取写入的目录，与原结构一致。
+    again, err := ReadDDL(dir, "db")
+
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if result := Compare(catalog, again, Options{}); len(result.Changes) > 0 {
+        t.Fatalf("pulled schema differs:\n%s", result.Script())
+    }
+}
@@ -0,0 +1,427 @@
+package mysqldiff
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/samber/lo"
+)
+
+// Renderer 将差异渲染为 SQL 语句。
+type Renderer struct {
+    Schema  Schema // 源数据库，用于表未指定字符集时的默认值
+    Options Options
+}
+
+// Render 返回使目标对象与源对象一致的 SQL 语句，每条语句以分号结尾。
+func (r Renderer) Render(change Change) []string {
+    switch c := change.(type) {
+    case *TableChange:
+        switch c.Type {
+        case ChangeCreate:
+            return []string{r.createTable(c)}
+        case ChangeAlter:
+            return r.alterTable(c)
+        case ChangeRename:
+            return append([]string{fmt.Sprintf("RENAME TABLE `%s` TO `%s`;", c.OldName, c.Table.TableName)}, r.alterTable(c)...)
+        case ChangeDrop:
+            return []string{fmt.Sprintf("DROP TABLE IF EXISTS `%s`;", c.Table.TableName)}
+        }
+    case *ViewChange:
+        switch c.Type {
+        case ChangeCreate:
+            return []string{fmt.Sprintf("CREATE ALGORITHM = UNDEFINED SQL SECURITY %s VIEW `%s` AS %s;",
+                c.To.SecurityType,
+                c.Name,
+                c.To.ViewDefinition,
+            )}
+        case ChangeReplace:
+            return []string{fmt.Sprintf("CREATE OR REPLACE ALGORITHM = UNDEFINED SQL SECURITY %s VIEW `%s` AS %s;",
+                c.To.SecurityType,
+                c.Name,
+                c.To.ViewDefinition,
+            )}
+        case ChangeDrop:
+            return []string{fmt.Sprintf("DROP VIEW IF EXISTS `%s`;", c.Name)}
+        }
+    case *DatabaseChange:
+        switch {
+        case c.Type == ChangeCreate:
+            return []string{getCreateDatabase(c.Name, *c.To), fmt.Sprintf("USE `%s`;", getQuotedName(c.Name))}
+        case c.ReadOnly != nil:
+            return []string{fmt.Sprintf("ALTER DATABASE `%s` READ ONLY = %d;", getQuotedName(c.Name), lo.Ternary(*c.ReadOnly, 1, 0))}
+        default:
+            return []string{r.alterDatabase(c)}
+        }
+    case *TriggerChange:
+        switch c.Type {
+        case ChangeCreate:
+            return []string{r.createTrigger(c)}
+        case ChangeReplace:
+            return []string{fmt.Sprintf("DROP TRIGGER IF EXISTS `%s`;", c.Name), r.createTrigger(c)}
+        case ChangeDrop:
+            return []string{fmt.Sprintf("DROP TRIGGER IF EXISTS `%s`;", c.Name)}
+        }
+    case *RoutineChange:
+        switch c.Type {
+        case ChangeCreate:
+            return []string{r.createRoutine(c)}
+        case ChangeAlter:
+            return []string{fmt.Sprintf("ALTER %s `%s`%s;", c.RoutineType, c.Name, r.routineCharacteristics(c.To.Routine, true))}
+        case ChangeReplace:
+            return []string{fmt.Sprintf("DROP %s IF EXISTS `%s`;", c.RoutineType, c.Name), r.createRoutine(c)}
+        case ChangeDrop:
+            return []string{fmt.Sprintf("DROP %s IF EXISTS `%s`;", c.RoutineType, c.Name)}
+        }
+    case *EventChange:
+        switch c.Type {
+        case ChangeCreate:
+            return []string{r.event("CREATE", c.To)}
+        case ChangeAlter:
+            return []string{r.event("ALTER", c.To)}
+        case ChangeDrop:
+            return []string{fmt.Sprintf("DROP EVENT IF EXISTS `%s`;", c.Name)}
+        }
+    }
+
+    return nil
+}
+
+// CREATE EVENT ... 或 ALTER EVENT ...
+func (r Renderer) event(statement string, event *Event) string {
+    var definer, comment string
+
+    if event.DEFINER != "" {
+        definer = fmt.Sprintf("DEFINER=%s ", getDefiner(event.DEFINER))
+    }
+
+    // ALTER 时总是指定注释，以便清空注释。
+    if r.Options.Comment && (statement == "ALTER" || event.EventComment != "") {
+        comment = fmt.Sprintf(" COMMENT '%s'", getColumnComment(event.EventComment))
+    }
+
+    return fmt.Sprintf("%s %sEVENT `%s` ON SCHEDULE %s ON COMPLETION %s %s%s DO %s;",
+        statement,
+        definer,
+        event.EventName,
+        getEventSchedule(*event, true, true),
+        event.OnCompletion,
+        getEventStatus(event.STATUS),
+        comment,
+        strings.TrimSpace(event.EventDefinition),
+    )
+}
+
+// CREATE PROCEDURE ... 或 CREATE FUNCTION ...
+func (r Renderer) createRoutine(c *RoutineChange) string {
+    var definer, returns string
+
+    if c.To.Routine.DEFINER != "" {
+        definer = fmt.Sprintf("DEFINER=%s ", getDefiner(c.To.Routine.DEFINER))
+    }
+
+    if c.RoutineType == "FUNCTION" {
+        returns = fmt.Sprintf(" RETURNS %s", c.To.Routine.DtdIdentifier.String)
+    }
+
+    return fmt.Sprintf("CREATE %s%s `%s`(%s)%s%s\n%s;",
+        definer,
+        c.RoutineType,
+        c.Name,
+        getRoutineParameters(c.To.Parameters),
+        returns,
+        r.routineCharacteristics(c.To.Routine, false),
+        strings.TrimSpace(c.To.Routine.RoutineDefinition.String),
+    )
+}
+
+// ALTER DATABASE ... 只修改不同的项。
+func (r Renderer) alterDatabase(c *DatabaseChange) string {
+    alterDatabaseSql := fmt.Sprintf("ALTER DATABASE `%s`", getQuotedName(c.Name))
+
+    if c.From.DefaultCollationName != c.To.DefaultCollationName {
+        alterDatabaseSql += fmt.Sprintf(" DEFAULT CHARACTER SET %s COLLATE %s", c.To.DefaultCharacterSetName, c.To.DefaultCollationName)
+    }
+
+    if c.From.DefaultEncryption.String != c.To.DefaultEncryption.String {
+        alterDatabaseSql += getDatabaseEncryption(*c.To)
+    }
+
+    return alterDatabaseSql + ";"
+}
+
+// routineCharacteristics 返回 COMMENT、DETERMINISTIC、SQL DATA ACCESS、SQL SECURITY，ALTER 时不能指定 DETERMINISTIC。
+func (r Renderer) routineCharacteristics(routine Routine, alter bool) string {
+    var characteristics string
+
+    if r.Options.Comment && (alter || routine.RoutineComment != "") {
+        characteristics += fmt.Sprintf(" COMMENT '%s'", getColumnComment(routine.RoutineComment))
+    }
+
+    if !alter {
+        if routine.IsDeterministic == "YES" {
+            characteristics += " DETERMINISTIC"
+        } else {
+            characteristics += " NOT DETERMINISTIC"
+        }
+    }
+
+    return characteristics + fmt.Sprintf(" %s SQL SECURITY %s", routine.SqlDataAccess, routine.SecurityType)
+}
+
+// CREATE TRIGGER ...
+func (r Renderer) createTrigger(c *TriggerChange) string {
+    var definer, order string
+
+    if c.To.DEFINER != "" {
+        definer = fmt.Sprintf("DEFINER=%s ", getDefiner(c.To.DEFINER))
+    }
+
+    if c.Follows != "" {
+        order = fmt.Sprintf("FOLLOWS `%s` ", c.Follows)
+    } else if c.Precedes != "" {
+        order = fmt.Sprintf("PRECEDES `%s` ", c.Precedes)
+    }
+
+    return fmt.Sprintf("CREATE %sTRIGGER `%s` %s %s ON `%s` FOR EACH ROW %s%s;",
+        definer,
+        c.Name,
+        c.To.ActionTiming,
+        c.To.EventManipulation,
+        c.To.EventObjectTable,
+        order,
+        strings.TrimSpace(c.To.ActionStatement),
+    )
+}
+
+// CREATE TABLE ...
+func (r Renderer) createTable(c *TableChange) string {
+    var (
+        createTableSql []string
+        createKeySql   []string
+    )
+
+    // KEY ...
+    for _, index := range c.Indexes {
+        createKeySql = append(createKeySql, fmt.Sprintf("  %s", getAddKeys(index.Name, index.Statistics, c.Table.ENGINE.String)))
+    }
+
+    // CONSTRAINT [symbol] FOREIGN KEY (col_name, ...) REFERENCES tbl_name (col_name,...) [ON DELETE reference_option] [ON UPDATE reference_option]
+    for _, foreignKey := range c.ForeignKeys {
+        createKeySql = append(createKeySql, fmt.Sprintf("  %s", getConstraint(foreignKey)))
+    }
+
+    for _, check := range c.Checks {
+        createKeySql = append(createKeySql, fmt.Sprintf("  %s", getCheck(check)))
+    }
+
+    createTableSql = append(createTableSql, fmt.Sprintf("CREATE TABLE IF NOT EXISTS `%s` (", c.Table.TableName))
+
+    // COLUMNS ...
+    for k, column := range c.Columns {
+        var (
+            dot = ""
+        )
+
+        if k < len(c.Columns)-1 || len(createKeySql) > 0 {
+            dot = ","
+        }
+
+        createTableSql = append(createTableSql, fmt.Sprintf("  %s%s", r.columnDefinition(column, column), dot))
+    }
+
+    if len(createKeySql) > 0 {
+        createTableSql = append(createTableSql, strings.Join(createKeySql, ",\n"))
+    }
+
+    cSql := ""
+
+    if r.Options.Comment {
+        cSql = fmt.Sprintf(" COMMENT='%s'", getColumnComment(c.Table.TableComment))
+    }
+
+    charset := r.Schema.DefaultCharacterSetName
+    collate := r.Schema.DefaultCollationName
+
+    if c.Table.TableCollation.Valid {
+        charset = strings.Split(c.Table.TableCollation.String, "_")[0]
+        collate = c.Table.TableCollation.String
+    }
+
+    aSql := ""
+
+    if r.Options.AutoIncrement != AutoIncrementNone && c.Table.AutoIncrement.Valid && c.Table.AutoIncrement.Int64 > 1 {
+        aSql = fmt.Sprintf(" AUTO_INCREMENT=%d", c.Table.AutoIncrement.Int64)
+    }
+
+    oSql := ""
+    options := getTableOptions(c.Table)
+
+    for _, name := range TableOptions {
+        if lo.Contains(r.Options.TableOptions, name) && options[name] != "" {
+            oSql += " " + getTableOptionClause(name, options[name])
+        }
+    }
+
+    createTableSql = append(createTableSql, fmt.Sprintf(") ENGINE=%s%s DEFAULT CHARSET=%s COLLATE=%s%s%s",
+        c.Table.ENGINE.String, aSql, charset, collate, oSql, cSql,
+    ))
+
+    // PARTITION BY ...
+    if len(c.Partitions) > 0 {
+        createTableSql = append(createTableSql, getPartitionBy(c.Partitions))
+    }
+
+    createTableSql[len(createTableSql)-1] += ";"
+
+    return strings.Join(createTableSql, "\n")
+}
+
+// ALTER TABLE ...
+func (r Renderer) alterTable(c *TableChange) []string {
+    var (
+        alterTableSql  []string
+        alterColumnSql []string
+        partitionSql   []string
+    )
+
+    for _, spec := range c.Specs {
+        switch s := spec.(type) {
+        case ColumnDropped:
+            alterColumnSql = append(alterColumnSql, fmt.Sprintf("  DROP COLUMN `%s`", s.Column.ColumnName))
+        case ColumnAdded:
+            alterColumnSql = append(alterColumnSql, fmt.Sprintf("  ADD COLUMN %s %s",
+                r.columnDefinition(s.Column, Column{}),
+                getColumnPosition(s.After),
+            ))
+        case ColumnModified:
+            if s.VisibilityOnly {
+                alterColumnSql = append(alterColumnSql, fmt.Sprintf("  ALTER COLUMN `%s` SET %s",
+                    s.To.ColumnName,
+                    lo.Ternary(isInvisibleColumn(s.To), "INVISIBLE", "VISIBLE"),
+                ))
+
+                continue
+            }
+
+            if !canModifyGeneration(s.From, s.To) {
+                // 删除后重新添加，生成列的值会重新计算。
+                alterColumnSql = append(alterColumnSql, fmt.Sprintf("  DROP COLUMN `%s`", s.From.ColumnName))
+                alterColumnSql = append(alterColumnSql, fmt.Sprintf("  ADD COLUMN %s %s",
+                    r.columnDefinition(s.To, Column{}),
+                    getColumnPosition(s.After),
+                ))
+
+                continue
+            }
+
+            alterColumnSql = append(alterColumnSql, fmt.Sprintf("  MODIFY COLUMN %s %s",
+                r.columnDefinition(s.To, s.From),
+                getColumnPosition(s.After),
+            ))
+        case ColumnRenamed:
+            alterColumnSql = append(alterColumnSql, fmt.Sprintf("  CHANGE COLUMN `%s` %s %s",
+                s.From.ColumnName,
+                r.columnDefinition(s.To, s.From),
+                getColumnPosition(s.After),
+            ))
+        case IndexDropped:
+            alterColumnSql = append(alterColumnSql, getDropKey(s.Index.Name))
+        case IndexAdded:
+            alterColumnSql = append(alterColumnSql, fmt.Sprintf("  ADD %s", getAddKeys(s.Index.Name, s.Index.Statistics, c.Table.ENGINE.String)))
+        case IndexModified:
+            if s.VisibilityOnly {
+                alterColumnSql = append(alterColumnSql, fmt.Sprintf("  ALTER INDEX `%s` %s",
+                    s.To.Name,
+                    lo.Ternary(isVisibleIndex(lo.Values(s.To.Statistics)[0]), "VISIBLE", "INVISIBLE"),
+                ))
+
+                continue
+            }
+
+            alterColumnSql = append(alterColumnSql, getDropKey(s.From.Name))
+            alterColumnSql = append(alterColumnSql, fmt.Sprintf("  ADD %s", getAddKeys(s.To.Name, s.To.Statistics, c.Table.ENGINE.String)))
+        case ForeignKeyDropped:
+            alterColumnSql = append(alterColumnSql, fmt.Sprintf("  DROP FOREIGN KEY `%s`", s.ForeignKey.Constraint.ConstraintName))
+        case ForeignKeyAdded:
+            alterColumnSql = append(alterColumnSql, fmt.Sprintf("  ADD %s", getConstraint(s.ForeignKey)))
+        case ForeignKeyModified:
+            // 同一语句中不能删除并重建同名外键。
+            alterTableSql = append(alterTableSql, fmt.Sprintf("ALTER TABLE `%s` DROP FOREIGN KEY `%s`;", c.Table.TableName, s.From.Constraint.ConstraintName))
+            alterColumnSql = append(alterColumnSql, fmt.Sprintf("  ADD %s", getConstraint(s.To)))
+        case CheckDropped:
+            alterColumnSql = append(alterColumnSql, fmt.Sprintf("  DROP CHECK `%s`", getQuotedName(s.Check.Constraint.ConstraintName)))
+        case CheckAdded:
+            alterColumnSql = append(alterColumnSql, fmt.Sprintf("  ADD %s", getAddCheck(s.Check)))
+        case CheckModified:
+            if getNormalizedSchemaExpression(s.From.CheckClause) == getNormalizedSchemaExpression(s.To.CheckClause) {
+                alterColumnSql = append(alterColumnSql, fmt.Sprintf("  ALTER CHECK `%s` %s",
+                    getQuotedName(s.From.Constraint.ConstraintName),
+                    lo.Ternary(isEnforced(s.To), "ENFORCED", "NOT ENFORCED"),
+                ))
+
+                continue
+            }
+
+            // 同一语句中不能删除并重建同名约束。
+            alterTableSql = append(alterTableSql, fmt.Sprintf("ALTER TABLE `%s` DROP CHECK `%s`;", c.Table.TableName, getQuotedName(s.From.Constraint.ConstraintName)))
+            alterColumnSql = append(alterColumnSql, fmt.Sprintf("  ADD %s", getAddCheck(s.To)))
+        case TableOptionChanged:
+            alterColumnSql = append(alterColumnSql, getTableOption(s))
+        case PartitioningRemoved:
+            // 先取消分区，以便随后修改主键、唯一索引。
+            alterTableSql = append(alterTableSql, fmt.Sprintf("ALTER TABLE `%s` REMOVE PARTITIONING;", c.Table.TableName))
+        case PartitioningChanged:
+            partitionSql = append(partitionSql, fmt.Sprintf("ALTER TABLE `%s`\n%s;", c.Table.TableName, getPartitionBy(s.To)))
+        case PartitionDropped:
+            partitionSql = append(partitionSql, fmt.Sprintf("ALTER TABLE `%s` DROP PARTITION %s;", c.Table.TableName, getPartitionNames(s.Partitions)))
+        case PartitionAdded:
+            partitionSql = append(partitionSql, fmt.Sprintf("ALTER TABLE `%s` ADD PARTITION (\n%s\n);",
+                c.Table.TableName,
+                strings.Join(getPartitionDefinitionSql(s.Partitions), ",\n"),
+            ))
+        case PartitionReorganized:
+            partitionSql = append(partitionSql, fmt.Sprintf("ALTER TABLE `%s` REORGANIZE PARTITION %s INTO (\n%s\n);",
+                c.Table.TableName,
+                getPartitionNames(s.From),
+                strings.Join(getPartitionDefinitionSql(s.To), ",\n"),
+            ))
+        }
+    }
+
+    // ALTER TABLE SQL ...
+    if r.Options.Tidb {
+        for _, alterColumn := range alterColumnSql {
+            alterTableSql = append(alterTableSql, fmt.Sprintf("ALTER TABLE `%s`\n%s;", c.Table.TableName, alterColumn))
+        }
+    } else if len(alterColumnSql) > 0 {
+        alterTableSql = append(alterTableSql, fmt.Sprintf("ALTER TABLE `%s`\n%s;", c.Table.TableName, strings.Join(alterColumnSql, ",\n")))
+    }
+
+    // 分区操作不能与其他修改写在同一语句中。
+    return append(alterTableSql, partitionSql...)
+}
+
+// columnDefinition 返回列定义，targetColumn 用于判断是否需要指定字符集。
+func (r Renderer) columnDefinition(column Column, targetColumn Column) string {
+    nullAbleDefault := getColumnNullAbleDefault(column, r.Options.Tidb)
+
+    if getGenerationType(column) != "" {
+        nullAbleDefault = getColumnGenerated(column)
+    }
+
+    definition := fmt.Sprintf("`%s` %s%s%s%s%s%s",
+        column.ColumnName, column.ColumnType,
+        getColumnSrid(column),
+        getCharacterSet(column, targetColumn),
+        nullAbleDefault,
+        getColumnExtra(column),
+        getColumnVisibility(column),
+    )
+
+    if r.Options.Comment {
+        definition = fmt.Sprintf("%s COMMENT '%s'", definition, getColumnComment(column.ColumnComment))
+    }
+
+    return definition
+}
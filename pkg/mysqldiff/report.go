@@ -0,0 +1,421 @@
+package mysqldiff
+
+import (
+    "database/sql"
+    "fmt"
+    htmltemplate "html/template"
+    "io"
+    "strings"
+    "text/template"
+
+    "github.com/samber/lo"
+)
+
+var (
+    changeTypeLabels = map[ChangeType]string{
+        ChangeCreate:  "新建",
+        ChangeAlter:   "修改",
+        ChangeReplace: "替换",
+        ChangeRename:  "重命名",
+        ChangeDrop:    "删除",
+    }
+
+    objectTypeLabels = map[string]string{
+        "DATABASE":  "库",
+        "TABLE":     "表",
+        "VIEW":      "视图",
+        "TRIGGER":   "触发器",
+        "PROCEDURE": "存储过程",
+        "FUNCTION":  "函数",
+        "EVENT":     "事件",
+    }
+
+    // specKinds 报告中统计的差异项，按显示顺序排列。
+    specKinds = []string{
+        "COLUMN_ADDED", "COLUMN_DROPPED", "COLUMN_MODIFIED", "COLUMN_RENAMED",
+        "INDEX_ADDED", "INDEX_DROPPED", "INDEX_MODIFIED",
+        "FOREIGN_KEY_ADDED", "FOREIGN_KEY_DROPPED", "FOREIGN_KEY_MODIFIED",
+        "CHECK_ADDED", "CHECK_DROPPED", "CHECK_MODIFIED",
+        "TABLE_OPTION_CHANGED",
+        "PARTITIONING_CHANGED", "PARTITIONING_REMOVED", "PARTITION_ADDED", "PARTITION_DROPPED", "PARTITION_REORGANIZED",
+    }
+
+    specKindLabels = map[string]string{
+        "COLUMN_ADDED":          "新增列",
+        "COLUMN_DROPPED":        "删除列",
+        "COLUMN_MODIFIED":       "修改列",
+        "COLUMN_RENAMED":        "重命名列",
+        "INDEX_ADDED":           "新增索引",
+        "INDEX_DROPPED":         "删除索引",
+        "INDEX_MODIFIED":        "修改索引",
+        "FOREIGN_KEY_ADDED":     "新增外键",
+        "FOREIGN_KEY_DROPPED":   "删除外键",
+        "FOREIGN_KEY_MODIFIED":  "修改外键",
+        "CHECK_ADDED":           "新增检查约束",
+        "CHECK_DROPPED":         "删除检查约束",
+        "CHECK_MODIFIED":        "修改检查约束",
+        "TABLE_OPTION_CHANGED":  "修改表选项",
+        "PARTITIONING_CHANGED":  "修改分区方式",
+        "PARTITIONING_REMOVED":  "取消分区",
+        "PARTITION_ADDED":       "新增分区",
+        "PARTITION_DROPPED":     "删除分区",
+        "PARTITION_REORGANIZED": "重组分区",
+    }
+)
+
+// report 报告模板数据。
+type report struct {
+    Schema  string
+    Counts  []reportCount
+    Objects []reportObject
+}
+
+type reportCount struct {
+    Name  string
+    Count int
+}
+
+type reportObject struct {
+    ObjectType string
+    OldName    string
+    Name       string
+    Type       string
+    Rows       []reportRow
+    Sql        string
+}
+
+// reportRow 一项差异，Old 为目标的值，New 为源的值。
+type reportRow struct {
+    Kind string
+    Name string
+    Old  string
+    New  string
+}
+
+const markdownReport = `# 数据库 {{code .Schema}} 差异报告
+{{if not .Objects}}
+没有差异。
+{{else}}
+| 项目 | 数量 |
+| --- | ---: |
+{{range .Counts}}| {{.Name}} | {{.Count}} |
+{{end}}{{range .Objects}}
+## {{.ObjectType}} {{with .OldName}}{{code .}} → {{end}}{{code .Name}}（{{.Type}}）
+{{if .Rows}}
+| 差异 | 名称 | 目标 | 源 |
+| --- | --- | --- | --- |
+{{range .Rows}}| {{cell .Kind}} | {{cell .Name}} | {{cell .Old}} | {{cell .New}} |
+{{end}}{{end}}
+<details>
+<summary>SQL</summary>
+
+` + "```sql" + `
+{{.Sql}}
+` + "```" + `
+
+</details>
+{{end}}{{end}}`
+
+const htmlReport = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>数据库 {{.Schema}} 差异报告</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", "PingFang SC", "Microsoft YaHei", sans-serif; margin: 2em; color: #24292f; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #d0d7de; padding: 4px 10px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+td.count { text-align: right; }
+code, pre { font-family: SFMono-Regular, Consolas, monospace; }
+pre { background: #f6f8fa; padding: 1em; overflow: auto; }
+</style>
+</head>
+<body>
+<h1>数据库 <code>{{.Schema}}</code> 差异报告</h1>
+{{if not .Objects}}<p>没有差异。</p>
+{{else}}<table>
+<tr><th>项目</th><th>数量</th></tr>
+{{range .Counts}}<tr><td>{{.Name}}</td><td class="count">{{.Count}}</td></tr>
+{{end}}</table>
+{{range .Objects}}<h2>{{.ObjectType}} {{with .OldName}}<code>{{.}}</code> → {{end}}<code>{{.Name}}</code>（{{.Type}}）</h2>
+{{if .Rows}}<table>
+<tr><th>差异</th><th>名称</th><th>目标</th><th>源</th></tr>
+{{range .Rows}}<tr><td>{{.Kind}}</td><td>{{.Name}}</td><td><code>{{.Old}}</code></td><td><code>{{.New}}</code></td></tr>
+{{end}}</table>
+{{end}}<details>
+<summary>SQL</summary>
+<pre><code>{{.Sql}}</code></pre>
+</details>
+{{end}}{{end}}</body>
+</html>
+`
+
+// WriteMarkdown 将差异报告以 Markdown 格式写入 w。
+func (r *Result) WriteMarkdown(w io.Writer) error {
+    tmpl := template.Must(template.New("markdown").Funcs(template.FuncMap{
+        "code": getMarkdownCode,
+        "cell": getMarkdownCell,
+    }).Parse(markdownReport))
+
+    return tmpl.Execute(w, r.report())
+}
+
+// WriteHTML 将差异报告以 HTML 格式写入 w。
+func (r *Result) WriteHTML(w io.Writer) error {
+    tmpl := htmltemplate.Must(htmltemplate.New("html").Parse(htmlReport))
+
+    return tmpl.Execute(w, r.report())
+}
+
+func (r *Result) report() report {
+    var (
+        document     = r.Document()
+        objectTypes  []string
+        changeCounts = make(map[string]map[ChangeType]int)
+        specCounts   = make(map[string]int)
+    )
+
+    rpt := report{Schema: document.Schema}
+
+    for _, change := range document.Changes {
+        if _, ok := changeCounts[change.ObjectType]; !ok {
+            objectTypes = append(objectTypes, change.ObjectType)
+            changeCounts[change.ObjectType] = make(map[ChangeType]int)
+        }
+
+        changeCounts[change.ObjectType][change.Type]++
+
+        object := reportObject{
+            ObjectType: getObjectTypeLabel(change.ObjectType),
+            OldName:    change.OldName,
+            Name:       change.Name,
+            Type:       changeTypeLabels[change.Type],
+            Sql:        strings.Join(change.Sql, "\n"),
+        }
+
+        if change.Old != nil || change.New != nil {
+            object.Rows = append(object.Rows, reportRow{
+                Kind: object.ObjectType,
+                Name: change.Name,
+                Old:  getReportValue(change.Old),
+                New:  getReportValue(change.New),
+            })
+        }
+
+        for _, spec := range change.Specs {
+            specCounts[spec.Kind]++
+
+            object.Rows = append(object.Rows, reportRow{
+                Kind: getSpecKindLabel(spec.Kind),
+                Name: getReportName(spec),
+                Old:  getReportValue(spec.Old),
+                New:  getReportValue(spec.New),
+            })
+        }
+
+        rpt.Objects = append(rpt.Objects, object)
+    }
+
+    for _, changeType := range []ChangeType{ChangeCreate, ChangeRename, ChangeAlter, ChangeReplace, ChangeDrop} {
+        for _, objectType := range objectTypes {
+            if count := changeCounts[objectType][changeType]; count > 0 {
+                rpt.Counts = append(rpt.Counts, reportCount{Name: changeTypeLabels[changeType] + getObjectTypeLabel(objectType), Count: count})
+            }
+        }
+    }
+
+    for _, kind := range specKinds {
+        if specCounts[kind] > 0 {
+            rpt.Counts = append(rpt.Counts, reportCount{Name: getSpecKindLabel(kind), Count: specCounts[kind]})
+        }
+    }
+
+    return rpt
+}
+
+// getReportName 重命名时返回 "旧名 → 新名"。
+func getReportName(spec DocumentSpec) string {
+    if spec.OldName != "" {
+        return spec.OldName + " → " + spec.Name
+    }
+
+    return spec.Name
+}
+
+func getObjectTypeLabel(objectType string) string {
+    if label, ok := objectTypeLabels[objectType]; ok {
+        return label
+    }
+
+    return objectType
+}
+
+func getSpecKindLabel(kind string) string {
+    if label, ok := specKindLabels[kind]; ok {
+        return label
+    }
+
+    return kind
+}
+
+// getReportValue 将属性格式化为一行文本。
+func getReportValue(value interface{}) string {
+    switch v := value.(type) {
+    case nil:
+        return ""
+    case string:
+        return v
+    case []string:
+        return strings.Join(v, "\n")
+    case DatabaseAttributes:
+        parts := []string{fmt.Sprintf("CHARACTER SET %s COLLATE %s", v.Charset, v.Collation)}
+
+        if v.Encryption != "" {
+            parts = append(parts, fmt.Sprintf("ENCRYPTION='%s'", v.Encryption[:1]))
+        }
+
+        if v.ReadOnly {
+            parts = append(parts, "READ ONLY")
+        }
+
+        return strings.Join(parts, " ")
+    case TableAttributes:
+        parts := []string{fmt.Sprintf("ENGINE=%s COLLATE=%s", v.Engine, v.Collation)}
+
+        for _, name := range TableOptions {
+            if value, ok := v.Options[name]; ok {
+                parts = append(parts, getTableOptionClause(name, value))
+            }
+        }
+
+        return strings.Join(append(parts, fmt.Sprintf("COMMENT='%s'", v.Comment)), " ")
+    case ColumnAttributes:
+        parts := []string{v.Type}
+
+        if v.Srid != nil {
+            parts = append(parts, fmt.Sprintf("SRID %d", *v.Srid))
+        }
+
+        if v.Collation != "" {
+            parts = append(parts, "COLLATE "+v.Collation)
+        }
+
+        if v.Generated != "" {
+            parts = append(parts, fmt.Sprintf("GENERATED ALWAYS AS (%s)", v.Generated))
+        }
+
+        if v.Nullable {
+            parts = append(parts, "NULL")
+        } else {
+            parts = append(parts, "NOT NULL")
+        }
+
+        if v.Default != nil {
+            parts = append(parts, "DEFAULT "+getColumnDefault(Column{
+                DataType:      getDataType(v.Type),
+                ColumnDefault: sql.NullString{String: *v.Default, Valid: true},
+                EXTRA:         v.Extra,
+            }, false))
+        }
+
+        if v.Extra != "" {
+            parts = append(parts, v.Extra)
+        }
+
+        if v.Comment != "" {
+            parts = append(parts, fmt.Sprintf("COMMENT '%s'", v.Comment))
+        }
+
+        if v.After != nil {
+            parts = append(parts, strings.TrimSpace(getColumnPosition(*v.After)))
+        }
+
+        return strings.Join(parts, " ")
+    case IndexAttributes:
+        kind := "KEY"
+
+        switch {
+        case v.Type == "FULLTEXT" || v.Type == "SPATIAL":
+            kind = v.Type + " KEY"
+        case v.Unique:
+            kind = "UNIQUE KEY"
+        }
+
+        value := fmt.Sprintf("%s (%s)", kind, strings.Join(v.Columns, ", "))
+
+        if v.Type == "BTREE" || v.Type == "HASH" {
+            value += " USING " + v.Type
+        }
+
+        if v.Parser != "" {
+            value += " WITH PARSER " + v.Parser
+        }
+
+        if v.Invisible {
+            value += " INVISIBLE"
+        }
+
+        return value
+    case ForeignKeyAttributes:
+        return fmt.Sprintf("(%s) REFERENCES %s (%s) ON DELETE %s ON UPDATE %s",
+            strings.Join(v.Columns, ", "),
+            v.ReferencedTable,
+            strings.Join(v.ReferencedColumns, ", "),
+            v.OnDelete, v.OnUpdate,
+        )
+    case CheckAttributes:
+        return fmt.Sprintf("CHECK (%s)%s", v.Clause, lo.Ternary(v.Enforced, "", " NOT ENFORCED"))
+    case TriggerAttributes:
+        return fmt.Sprintf("%s %s ON %s (ACTION_ORDER %d) DEFINER=%s: %s", v.Timing, v.Event, v.Table, v.Order, v.Definer, v.Body)
+    case RoutineAttributes:
+        parts := []string{fmt.Sprintf("(%s)", v.Parameters)}
+
+        if v.Returns != "" {
+            parts = append(parts, "RETURNS "+v.Returns)
+        }
+
+        if v.Deterministic {
+            parts = append(parts, "DETERMINISTIC")
+        } else {
+            parts = append(parts, "NOT DETERMINISTIC")
+        }
+
+        parts = append(parts, v.DataAccess, "SQL SECURITY "+v.Security)
+
+        if v.Comment != "" {
+            parts = append(parts, fmt.Sprintf("COMMENT '%s'", v.Comment))
+        }
+
+        return fmt.Sprintf("%s DEFINER=%s: %s", strings.Join(parts, " "), v.Definer, v.Body)
+    case EventAttributes:
+        value := fmt.Sprintf("ON SCHEDULE %s ON COMPLETION %s %s", v.Schedule, v.OnCompletion, v.Status)
+
+        if v.Comment != "" {
+            value += fmt.Sprintf(" COMMENT '%s'", v.Comment)
+        }
+
+        return fmt.Sprintf("%s DEFINER=%s: %s", value, v.Definer, v.Body)
+    case ViewAttributes:
+        return fmt.Sprintf("SQL SECURITY %s AS %s", v.SecurityType, v.Definition)
+    }
+
+    return fmt.Sprint(value)
+}
+
+// getMarkdownCode 返回行内代码，内容含反引号时加长分隔符。
+func getMarkdownCode(s string) string {
+    if strings.Contains(s, "`") {
+        return "`` " + s + " ``"
+    }
+
+    return "`" + s + "`"
+}
+
+// getMarkdownCell 转义表格单元格中的竖线与换行。
+func getMarkdownCell(s string) string {
+    s = strings.ReplaceAll(s, "|", "\\|")
+    s = strings.ReplaceAll(s, "\r\n", "\n")
+
+    return strings.ReplaceAll(s, "\n", "<br>")
+}
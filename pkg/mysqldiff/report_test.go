@@ -0,0 +1,77 @@
+package mysqldiff
+
+import (
+    "bytes"
+    "strings"
+    "testing"
+)
+
+// reportResult 新建表 c，表 t 删除列 m、新增带注释的列 n。
+func reportResult(t *testing.T, comment string) *Result {
+    t.Helper()
+
+    source := mustParseDDL(t, "CREATE TABLE t (id int NOT NULL, n varchar(10) COMMENT '"+comment+"'); CREATE TABLE c (id int);")
+    target := mustParseDDL(t, "CREATE TABLE t (id int NOT NULL, m int);")
+
+    return Compare(source, target, Options{Comment: true})
+}
+
+func TestWriteMarkdown(t *testing.T) {
+    var b bytes.Buffer
+
+    if err := reportResult(t, "a|b").WriteMarkdown(&b); err != nil {
+        t.Fatal(err)
+    }
+
+    for _, s := range []string{
+        "# 数据库 `db` 差异报告\n",
+        "| 新建表 | 1 |\n| 修改表 | 1 |\n| 新增列 | 2 |\n| 删除列 | 1 |\n",
+        "## 表 `c`（新建）\n",
+        "## 表 `t`（修改）\n",
+        "| 删除列 | m | int NULL |  |\n",
+        // 单元格中的竖线需要转义。
+        "| 新增列 | n |  | varchar(10) COLLATE utf8mb4_0900_ai_ci NULL COMMENT 'a\\|b' AFTER `id` |\n",
+        "```sql\nALTER TABLE `t`\n  DROP COLUMN `m`,\n",
+    } {
+        if !strings.Contains(b.String(), s) {
+            t.Errorf("markdown does not contain %q:\n%s", s, b.String())
+        }
+    }
+}
+
+func TestWriteMarkdownEmpty(t *testing.T) {
+    var b bytes.Buffer
+
+    catalog := mustParseDDL(t, "CREATE TABLE t (id int);")
+
+    if err := Compare(catalog, catalog, Options{}).WriteMarkdown(&b); err != nil {
+        t.Fatal(err)
+    }
+
+    if !strings.Contains(b.String(), "没有差异。") {
+        t.Fatalf("markdown = %q", b.String())
+    }
+}
+
+func TestWriteHTML(t *testing.T) {
+    var b bytes.Buffer
+
+    if err := reportResult(t, "<b>").WriteHTML(&b); err != nil {
+        t.Fatal(err)
+    }
+
+    for _, s := range []string{
+        "<h1>数据库 <code>db</code> 差异报告</h1>",
+        "<tr><td>新增列</td><td class=\"count\">2</td></tr>",
+        "<h2>表 <code>t</code>（修改）</h2>",
+        "COMMENT &#39;&lt;b&gt;&#39;",
+    } {
+        if !strings.Contains(b.String(), s) {
+            t.Errorf("html does not contain %q:\n%s", s, b.String())
+        }
+    }
+
+    if strings.Contains(b.String(), "<b>") {
+        t.Errorf("html is not escaped:\n%s", b.String())
+    }
+}
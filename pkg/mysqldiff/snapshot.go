@@ -0,0 +1,73 @@
+package mysqldiff
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+    "os"
+    "time"
+)
+
+// SnapshotVersion 快照格式版本，Catalog 中序列化的字段变化时递增。
+//
+//  1. 表、列、索引、外键与视图。
+//  2. 增加触发器、存储过程与函数、事件、分区、CHECK 约束、扩展表选项、库的默认加密与只读、全文索引的解析器、函数索引与生成列。
+const SnapshotVersion = 2
+
+// Snapshot 数据库结构快照。
+type Snapshot struct {
+    Version   int
+    CreatedAt time.Time
+    Catalog   *Catalog
+}
+
+// WriteSnapshot 将数据库结构以 JSON 格式写入 w。
+func WriteSnapshot(w io.Writer, catalog *Catalog) error {
+    encoder := json.NewEncoder(w)
+    encoder.SetIndent("", "    ")
+
+    return encoder.Encode(Snapshot{
+        Version:   SnapshotVersion,
+        CreatedAt: time.Now(),
+        Catalog:   catalog,
+    })
+}
+
+// ReadSnapshot 从 r 读取快照中的数据库结构。
+func ReadSnapshot(r io.Reader) (*Catalog, error) {
+    var snapshot Snapshot
+
+    if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+        return nil, fmt.Errorf("快照格式错误：%w", err)
+    }
+
+    if snapshot.Version <= 0 || snapshot.Version > SnapshotVersion {
+        return nil, fmt.Errorf("不支持的快照版本 `%d`。(当前版本: %d)", snapshot.Version, SnapshotVersion)
+    }
+
+    // 旧版本的快照缺少之后增加的结构，比对时会被当作已删除。
+    if snapshot.Version < SnapshotVersion {
+        return nil, fmt.Errorf("快照版本 `%d` 过旧，缺少之后增加的结构，请用 snapshot 重新导出。(当前版本: %d)", snapshot.Version, SnapshotVersion)
+    }
+
+    if snapshot.Catalog == nil {
+        return nil, fmt.Errorf("快照中没有数据库结构。")
+    }
+
+    snapshot.Catalog.build()
+
+    return snapshot.Catalog, nil
+}
+
+// ReadSnapshotFile 读取快照文件。
+func ReadSnapshotFile(name string) (*Catalog, error) {
+    f, err := os.Open(name)
+
+    if err != nil {
+        return nil, err
+    }
+
+    defer f.Close()
+
+    return ReadSnapshot(f)
+}
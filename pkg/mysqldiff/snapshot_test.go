@@ -0,0 +1,99 @@
+package mysqldiff
+
+import (
+    "bytes"
+    "crypto/sha256"
+    "encoding/hex"
+    "fmt"
+    "reflect"
+    "strings"
+    "testing"
+)
+
+// snapshotFingerprint SnapshotVersion 对应的 Catalog 序列化字段的摘要。
+const snapshotFingerprint = "56e500e624af419e8d409bb4c4ccae4a2e7e56257afa21dfc4c644be0816daee"
+
+// getTypeFingerprint 返回类型中序列化字段的名称与类型。
+func getTypeFingerprint(t reflect.Type, seen map[reflect.Type]bool) string {
+    switch t.Kind() {
+    case reflect.Pointer, reflect.Slice:
+        return "[" + getTypeFingerprint(t.Elem(), seen) + "]"
+    case reflect.Map:
+        return "map[" + getTypeFingerprint(t.Key(), seen) + "]" + getTypeFingerprint(t.Elem(), seen)
+    case reflect.Struct:
+        if seen[t] || t.PkgPath() != reflect.TypeOf(Catalog{}).PkgPath() {
+            return t.String()
+        }
+
+        seen[t] = true
+
+        var fields []string
+
+        for i := 0; i < t.NumField(); i++ {
+            if field := t.Field(i); field.IsExported() && field.Tag.Get("json") != "-" {
+                fields = append(fields, field.Name+" "+getTypeFingerprint(field.Type, seen))
+            }
+        }
+
+        return t.Name() + "{" + strings.Join(fields, ";") + "}"
+    }
+
+    return t.String()
+}
+
+func TestSnapshotVersion(t *testing.T) {
+    sum := sha256.Sum256([]byte(getTypeFingerprint(reflect.TypeOf(Catalog{}), make(map[reflect.Type]bool))))
+
+    if fingerprint := hex.EncodeToString(sum[:]); fingerprint != snapshotFingerprint {
+        t.Fatalf("Catalog 的序列化字段已变化，请递增 SnapshotVersion（当前 %d）并将 snapshotFingerprint 改为 %q", SnapshotVersion, fingerprint)
+    }
+}
+
+func TestSnapshot(t *testing.T) {
+    catalog := mustParseDDL(t, `
+CREATE TABLE t (
+    id int NOT NULL AUTO_INCREMENT,
+    status varchar(10) NOT NULL DEFAULT 'new',
+    PRIMARY KEY (id),
+    CONSTRAINT c1 CHECK (status IN ('new', 'done'))
+) PARTITION BY HASH (id) PARTITIONS 4;
+CREATE VIEW v AS SELECT id FROM t;
+`)
+
+    var b bytes.Buffer
+
+    if err := WriteSnapshot(&b, catalog); err != nil {
+        t.Fatal(err)
+    }
+
+    snapshot, err := ReadSnapshot(bytes.NewReader(b.Bytes()))
+
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if result := Compare(catalog, snapshot, Options{Comment: true, Foreign: true, TableOptions: TableOptions}); len(result.Changes) > 0 {
+        t.Fatalf("snapshot differs:\n%s", result.Script())
+    }
+}
+
+func TestReadSnapshotVersion(t *testing.T) {
+    tests := []struct {
+        version int
+        err     string
+    }{
+        {0, "不支持的快照版本"},
+        {SnapshotVersion - 1, "过旧"},
+        {SnapshotVersion + 1, "不支持的快照版本"},
+    }
+
+    for _, tt := range tests {
+        t.Run(fmt.Sprint(tt.version), func(t *testing.T) {
+            _, err := ReadSnapshot(strings.NewReader(fmt.Sprintf(`{"Version": %d, "Catalog": {}}`, tt.version)))
+
+            if err == nil || !strings.Contains(err.Error(), tt.err) {
+                t.Fatalf("ReadSnapshot() error = %v, want %q", err, tt.err)
+            }
+        })
+    }
+}